@@ -33,8 +33,11 @@ const (
 )
 
 var (
-	driverAddress = flag.String("driver-address", "unix:///var/lib/cosi/cosi.sock", "driver address for the socket")
-	driverPrefix  = flag.String("driver-prefix", "", "prefix for COSI driver, e.g. <prefix>.scality.com")
+	driverAddress     = flag.String("driver-address", "unix:///var/lib/cosi/cosi.sock", "driver address for the socket")
+	driverPrefix      = flag.String("driver-prefix", "", "prefix for COSI driver, e.g. <prefix>.scality.com")
+	blockPublicAccess = flag.Bool("block-public-access", true, "make new buckets private and block public access unless the BucketClass sets COSI_BUCKET_BLOCK_PUBLIC_ACCESS to false")
+	iamAuthentication = flag.Bool("iam-authentication", false, "accept BucketAccessClasses with the IAM authentication type, only with a COSI sidecar that copies the role ARN and STS endpoint into the BucketInfo secret")
+	secretSelector    = flag.String("secret-label-selector", driver.DefaultSecretLabelSelector, "label selector of the object storage provider secrets watched in the driver namespace, all its secrets if empty")
 )
 
 func init() {
@@ -49,13 +52,18 @@ func init() {
 		klog.Warning("No driver prefix provided, using default prefix")
 	}
 
-	klog.InfoS("COSI driver startup configuration", "driverAddress", *driverAddress, "driverPrefix", *driverPrefix)
+	klog.InfoS("COSI driver startup configuration", "driverAddress", *driverAddress, "driverPrefix", *driverPrefix,
+		"blockPublicAccess", *blockPublicAccess, "secretLabelSelector", *secretSelector, "iamAuthentication", *iamAuthentication)
 }
 
 func run(ctx context.Context) error {
 	driverName := *driverPrefix + "." + provisionerName
 
-	identityServer, bucketProvisioner, err := driver.CreateDriver(ctx, driverName)
+	identityServer, bucketProvisioner, err := driver.CreateDriver(ctx, driverName, driver.Options{
+		BlockPublicAccess:   *blockPublicAccess,
+		SecretLabelSelector: *secretSelector,
+		IAMAuthentication:   *iamAuthentication,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to initialize Scality driver: %w", err)
 	}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	s3client "github.com/scality/cosi/pkg/util/s3client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// resolveExistingBucketName returns the name of the pre-existing bucket to adopt, empty if none.
+func resolveExistingBucketName(parameters map[string]string) (string, error) {
+	name := parameters["COSI_EXISTING_BUCKET_NAME"]
+	if name == "" {
+		return "", nil
+	}
+
+	if parameters["COSI_BUCKET_NAME_TEMPLATE"] != "" || parameters["COSI_BUCKET_NAME_PREFIX"] != "" {
+		return "", status.Error(codes.InvalidArgument,
+			"COSI_EXISTING_BUCKET_NAME and COSI_BUCKET_NAME_TEMPLATE or COSI_BUCKET_NAME_PREFIX are mutually exclusive")
+	}
+	if err := validateBucketName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// checkAdoptionConflict rejects the adoption of a bucket that another Bucket object already holds. Every claim of a
+// BucketClass setting COSI_EXISTING_BUCKET_NAME resolves to the same bucket, which the deletion of one of them would
+// delete for all with COSI_EXISTING_BUCKET_DELETE. Bucket objects are only indexed once the sidecar records the ID
+// of their bucket, concurrent first adoptions of the same bucket are not detected.
+func checkAdoptionConflict(informer *BucketInformer, cosiBucketName, bucketName string) error {
+	if informer == nil {
+		return nil
+	}
+
+	buckets, err := informer.bucketsByID(bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to look up bucket objects", "bucketName", bucketName)
+		return status.Error(codes.Internal, "failed to look up bucket objects")
+	}
+	for _, bucket := range buckets {
+		if bucket.Name != cosiBucketName {
+			klog.V(3).InfoS("Existing bucket is already adopted", "bucketName", bucketName, "cosiBucketName", bucket.Name)
+			return status.Errorf(codes.AlreadyExists, "existing bucket %s is already adopted by Bucket %s", bucketName, bucket.Name)
+		}
+	}
+	return nil
+}
+
+// adoptBucket checks that a pre-existing bucket exists and belongs to the account of the configured credentials.
+func adoptBucket(ctx context.Context, s3Client *s3client.S3Client, bucketName string) error {
+	if err := s3Client.HeadBucket(ctx, bucketName); err != nil {
+		var notFound *s3types.NotFound
+		var apiErr smithy.APIError
+		switch {
+		case errors.As(err, &notFound):
+			klog.V(3).InfoS("Existing bucket not found", "bucketName", bucketName)
+			return status.Errorf(codes.NotFound, "existing bucket not found: %s", bucketName)
+		case errors.As(err, &apiErr) && (apiErr.ErrorCode() == "Forbidden" || apiErr.ErrorCode() == "AccessDenied"):
+			klog.V(3).InfoS("Existing bucket is not accessible with the configured credentials", "bucketName", bucketName)
+			return status.Errorf(codes.PermissionDenied, "existing bucket is not accessible with the configured credentials: %s", bucketName)
+		}
+		klog.ErrorS(err, "Failed to check existing bucket", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to check existing bucket: %s", bucketName)
+	}
+
+	owned, err := s3Client.IsBucketOwner(ctx, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to check existing bucket owner", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to check existing bucket owner: %s", bucketName)
+	}
+	if !owned {
+		klog.V(3).InfoS("Existing bucket is owned by another account", "bucketName", bucketName)
+		return status.Errorf(codes.PermissionDenied, "existing bucket is not owned by the configured credentials: %s", bucketName)
+	}
+
+	klog.V(3).InfoS("Adopted existing bucket", "bucketName", bucketName)
+	return nil
+}
+
+// isAdoptedBucket reports whether a bucket predates COSI, either adopted through COSI_EXISTING_BUCKET_NAME
+// or statically provisioned with a Bucket object referencing an existing bucket ID, which fetchBucketParameters
+// reports as COSI_EXISTING_BUCKET_NAME.
+func isAdoptedBucket(parameters map[string]string) bool {
+	return parameters["COSI_EXISTING_BUCKET_NAME"] != ""
+}
+
+// parseExistingBucketDelete returns whether adopted buckets may be deleted, which must be explicitly allowed.
+func parseExistingBucketDelete(parameters map[string]string) (bool, error) {
+	value := parameters["COSI_EXISTING_BUCKET_DELETE"]
+	if value == "" {
+		return false, nil
+	}
+
+	allowed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, status.Errorf(codes.InvalidArgument, "invalid COSI_EXISTING_BUCKET_DELETE value: %s", value)
+	}
+	return allowed, nil
+}
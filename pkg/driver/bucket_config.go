@@ -0,0 +1,726 @@
+/*
+Copyright 2024 Scality, Inc.
+Licensed under the Apache License, Version 2.0 (the "License");
+You may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	s3client "github.com/scality/cosi/pkg/util/s3client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// bucketConfig holds the bucket settings requested through BucketClass parameters.
+// Zero values mean the setting was not requested and is left to the object storage defaults.
+type bucketConfig struct {
+	Versioning          s3types.BucketVersioningStatus
+	ObjectLockEnabled   bool
+	ObjectLockRetention *s3types.DefaultRetention
+	Encryption          *s3types.ServerSideEncryptionByDefault
+	Lifecycle           *s3types.BucketLifecycleConfiguration
+	Tags                map[string]string
+	Quota               int64
+	CORSRules           []s3types.CORSRule
+	Policy              string
+	Notification        *s3types.NotificationConfiguration
+	Replication         *bucketReplication
+
+	// BlockPublicAccess applies to the buckets created by the driver, including on the retries of an interrupted
+	// creation. Adopted buckets are only made private when the BucketClass explicitly sets
+	// COSI_BUCKET_BLOCK_PUBLIC_ACCESS, as they may be public on purpose.
+	BlockPublicAccess         bool
+	BlockPublicAccessExplicit bool
+}
+
+const (
+	// prefix of the tags set by the driver, it cannot be used in COSI_BUCKET_TAGS
+	reservedTagPrefix = "cosi.scality.com/"
+
+	provisionerTag = reservedTagPrefix + "provisioner"
+	bucketTag      = reservedTagPrefix + "bucket"
+
+	// region of the buckets created without a location constraint
+	defaultBucketRegion = "us-east-1"
+
+	maxBucketTags     = 50
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// resolveBucketConfig validates the BucketClass parameters, and fetches the documents they reference.
+// Invalid parameters return codes.InvalidArgument before anything is created.
+// blockPublicAccess is the driver default, which the BucketClass can override.
+func resolveBucketConfig(ctx context.Context, clientset kubernetes.Interface, bucketName string, parameters map[string]string,
+	blockPublicAccess bool) (*bucketConfig, error) {
+	config, err := parseBucketConfig(parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.BlockPublicAccess, err = parseBlockPublicAccess(parameters, blockPublicAccess); err != nil {
+		return nil, err
+	}
+	config.BlockPublicAccessExplicit = config.BlockPublicAccess && parameters["COSI_BUCKET_BLOCK_PUBLIC_ACCESS"] != ""
+
+	if config.Lifecycle, err = resolveLifecycleConfiguration(ctx, clientset, parameters); err != nil {
+		return nil, err
+	}
+
+	if config.CORSRules, err = resolveCORSConfiguration(ctx, clientset, parameters); err != nil {
+		return nil, err
+	}
+
+	if config.Policy, err = resolveBucketPolicy(ctx, clientset, bucketName, parameters); err != nil {
+		return nil, err
+	}
+	if config.Policy != "" && config.BlockPublicAccess {
+		if err := checkPublicBucketPolicy(config.Policy); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Notification, err = resolveBucketNotification(ctx, clientset, parameters); err != nil {
+		return nil, err
+	}
+
+	if config.Replication, err = resolveBucketReplication(bucketName, parameters); err != nil {
+		return nil, err
+	}
+	if config.Replication != nil {
+		switch config.Versioning {
+		case s3types.BucketVersioningStatusSuspended:
+			return nil, status.Error(codes.InvalidArgument, "replication requires versioning, COSI_BUCKET_VERSIONING cannot be Suspended")
+		case "":
+			config.Versioning = s3types.BucketVersioningStatusEnabled
+		}
+	}
+
+	return config, nil
+}
+
+func parseBucketConfig(parameters map[string]string) (*bucketConfig, error) {
+	config := &bucketConfig{}
+
+	if value := parameters["COSI_BUCKET_VERSIONING"]; value != "" {
+		switch versioning := s3types.BucketVersioningStatus(value); versioning {
+		case s3types.BucketVersioningStatusEnabled, s3types.BucketVersioningStatusSuspended:
+			config.Versioning = versioning
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_VERSIONING value: %s, must be Enabled or Suspended", value)
+		}
+	}
+
+	objectLockEnabled, err := parseObjectLockEnabled(parameters)
+	if err != nil {
+		return nil, err
+	}
+	objectLockRetention, err := parseObjectLockRetention(parameters)
+	if err != nil {
+		return nil, err
+	}
+	if objectLockRetention != nil && !objectLockEnabled {
+		return nil, status.Error(codes.InvalidArgument, "object lock retention requires COSI_BUCKET_OBJECT_LOCK_ENABLED to be true")
+	}
+	if objectLockEnabled && config.Versioning == s3types.BucketVersioningStatusSuspended {
+		return nil, status.Error(codes.InvalidArgument, "object lock requires versioning, COSI_BUCKET_VERSIONING cannot be Suspended")
+	}
+	config.ObjectLockEnabled = objectLockEnabled
+	config.ObjectLockRetention = objectLockRetention
+
+	if config.Encryption, err = parseEncryption(parameters); err != nil {
+		return nil, err
+	}
+
+	if config.Tags, err = parseBucketTags(parameters["COSI_BUCKET_TAGS"]); err != nil {
+		return nil, err
+	}
+
+	if config.Quota, err = parseBucketQuota(parameters["COSI_BUCKET_QUOTA"]); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func parseObjectLockEnabled(parameters map[string]string) (bool, error) {
+	value := parameters["COSI_BUCKET_OBJECT_LOCK_ENABLED"]
+	if value == "" {
+		return false, nil
+	}
+
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_OBJECT_LOCK_ENABLED value: %s", value)
+	}
+	return enabled, nil
+}
+
+// parseObjectLockRetention returns the default retention rule, nil if none is requested.
+// A rule needs a mode and exactly one of a number of days or years.
+func parseObjectLockRetention(parameters map[string]string) (*s3types.DefaultRetention, error) {
+	mode := parameters["COSI_BUCKET_OBJECT_LOCK_MODE"]
+	days := parameters["COSI_BUCKET_OBJECT_LOCK_RETENTION_DAYS"]
+	years := parameters["COSI_BUCKET_OBJECT_LOCK_RETENTION_YEARS"]
+	if mode == "" && days == "" && years == "" {
+		return nil, nil
+	}
+
+	retention := &s3types.DefaultRetention{}
+	switch retentionMode := s3types.ObjectLockRetentionMode(mode); retentionMode {
+	case s3types.ObjectLockRetentionModeGovernance, s3types.ObjectLockRetentionModeCompliance:
+		retention.Mode = retentionMode
+	case "":
+		return nil, status.Error(codes.InvalidArgument, "COSI_BUCKET_OBJECT_LOCK_MODE is required for an object lock retention period")
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_OBJECT_LOCK_MODE value: %s, must be GOVERNANCE or COMPLIANCE", mode)
+	}
+
+	switch {
+	case days != "" && years != "":
+		return nil, status.Error(codes.InvalidArgument, "COSI_BUCKET_OBJECT_LOCK_RETENTION_DAYS and COSI_BUCKET_OBJECT_LOCK_RETENTION_YEARS are mutually exclusive")
+	case days != "":
+		period, err := parsePositiveInt32("COSI_BUCKET_OBJECT_LOCK_RETENTION_DAYS", days)
+		if err != nil {
+			return nil, err
+		}
+		retention.Days = aws.Int32(period)
+	case years != "":
+		period, err := parsePositiveInt32("COSI_BUCKET_OBJECT_LOCK_RETENTION_YEARS", years)
+		if err != nil {
+			return nil, err
+		}
+		retention.Years = aws.Int32(period)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "COSI_BUCKET_OBJECT_LOCK_RETENTION_DAYS or COSI_BUCKET_OBJECT_LOCK_RETENTION_YEARS is required for an object lock mode")
+	}
+
+	return retention, nil
+}
+
+func parsePositiveInt32(name, value string) (int32, error) {
+	number, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || number <= 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid %s value: %s, must be a positive integer", name, value)
+	}
+	return int32(number), nil
+}
+
+// parseEncryption returns the default encryption of the bucket, nil if none is requested.
+// SSE-KMS uses the default KMS key of the object storage when no key ID is provided.
+func parseEncryption(parameters map[string]string) (*s3types.ServerSideEncryptionByDefault, error) {
+	algorithm := parameters["COSI_BUCKET_ENCRYPTION"]
+	keyID := parameters["COSI_BUCKET_ENCRYPTION_KMS_KEY_ID"]
+
+	switch s3types.ServerSideEncryption(algorithm) {
+	case "":
+		if keyID != "" {
+			return nil, status.Error(codes.InvalidArgument, "COSI_BUCKET_ENCRYPTION_KMS_KEY_ID requires COSI_BUCKET_ENCRYPTION to be aws:kms")
+		}
+		return nil, nil
+	case s3types.ServerSideEncryptionAes256:
+		if keyID != "" {
+			return nil, status.Error(codes.InvalidArgument, "COSI_BUCKET_ENCRYPTION_KMS_KEY_ID cannot be used with AES256 encryption")
+		}
+		return &s3types.ServerSideEncryptionByDefault{SSEAlgorithm: s3types.ServerSideEncryptionAes256}, nil
+	case s3types.ServerSideEncryptionAwsKms:
+		encryption := &s3types.ServerSideEncryptionByDefault{SSEAlgorithm: s3types.ServerSideEncryptionAwsKms}
+		if keyID != "" {
+			encryption.KMSMasterKeyID = aws.String(keyID)
+		}
+		return encryption, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_ENCRYPTION value: %s, must be AES256 or aws:kms", algorithm)
+	}
+}
+
+// parseBucketTags parses a comma separated list of key=value tags.
+func parseBucketTags(value string) (map[string]string, error) {
+	tags := map[string]string{}
+	if strings.TrimSpace(value) == "" {
+		return tags, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		key, tagValue, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		tagValue = strings.TrimSpace(tagValue)
+		switch {
+		case !found || key == "":
+			return nil, status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_TAGS entry: %q, must be key=value", pair)
+		case strings.HasPrefix(key, reservedTagPrefix):
+			return nil, status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_TAGS key: %s, the %s prefix is reserved", key, reservedTagPrefix)
+		case len(key) > maxTagKeyLength || len(tagValue) > maxTagValueLength:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_TAGS entry: %q, keys are limited to %d characters and values to %d",
+				pair, maxTagKeyLength, maxTagValueLength)
+		}
+		if _, exists := tags[key]; exists {
+			return nil, status.Errorf(codes.InvalidArgument, "duplicate COSI_BUCKET_TAGS key: %s", key)
+		}
+		tags[key] = tagValue
+	}
+
+	return tags, nil
+}
+
+// parseBucketQuota parses a size in bytes, with optional Kubernetes quantity suffixes such as 500Gi or 1T.
+func parseBucketQuota(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil || quantity.Sign() <= 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_QUOTA value: %s, must be a positive size in bytes such as 500Gi", value)
+	}
+	return quantity.Value(), nil
+}
+
+// addTags merges the tags set by the driver with the ones requested by the BucketClass.
+func (config *bucketConfig) addTags(tags map[string]string) error {
+	for key, value := range tags {
+		config.Tags[key] = value
+	}
+	if len(config.Tags) > maxBucketTags {
+		return status.Errorf(codes.InvalidArgument, "too many bucket tags: %d, at most %d are allowed including the ones set by the driver",
+			len(config.Tags), maxBucketTags)
+	}
+	return nil
+}
+
+// createOptions returns the settings that must be passed to CreateBucket, as they cannot be changed afterwards.
+func (config *bucketConfig) createOptions() s3client.BucketOptions {
+	options := s3client.BucketOptions{ObjectLockEnabled: config.ObjectLockEnabled}
+	if config.BlockPublicAccess {
+		options.ACL = s3types.BucketCannedACLPrivate
+	}
+	return options
+}
+
+// applyBucketConfig configures a bucket that was just created.
+// The clientset fetches the object storage provider secret of the replication destination.
+func applyBucketConfig(ctx context.Context, s *ProvisionerServer, s3Client *s3client.S3Client, bucketName string, config *bucketConfig) error {
+	if config.BlockPublicAccess {
+		if err := blockPublicAccess(ctx, s3Client, bucketName); err != nil {
+			return err
+		}
+	}
+
+	if config.Versioning != "" {
+		if err := putBucketVersioning(ctx, s3Client, bucketName, config.Versioning); err != nil {
+			return err
+		}
+	}
+
+	if config.ObjectLockRetention != nil {
+		if err := putObjectLockRetention(ctx, s3Client, bucketName, config.ObjectLockRetention); err != nil {
+			return err
+		}
+	}
+
+	if config.Encryption != nil {
+		if err := putBucketEncryption(ctx, s3Client, bucketName, config.Encryption); err != nil {
+			return err
+		}
+	}
+
+	if config.Lifecycle != nil {
+		if err := putBucketLifecycle(ctx, s3Client, bucketName, config.Lifecycle); err != nil {
+			return err
+		}
+	}
+
+	if len(config.Tags) > 0 {
+		if err := putBucketTagging(ctx, s3Client, bucketName, config.Tags); err != nil {
+			return err
+		}
+	}
+
+	if config.Quota > 0 {
+		if err := putBucketQuota(ctx, s3Client, bucketName, config.Quota); err != nil {
+			return err
+		}
+	}
+
+	if len(config.CORSRules) > 0 {
+		if err := putBucketCors(ctx, s3Client, bucketName, config.CORSRules); err != nil {
+			return err
+		}
+	}
+
+	if config.Policy != "" {
+		if err := putBucketPolicy(ctx, s3Client, bucketName, config.Policy); err != nil {
+			return err
+		}
+	}
+
+	if config.Notification != nil {
+		if err := putBucketNotification(ctx, s3Client, bucketName, config.Notification); err != nil {
+			return err
+		}
+	}
+
+	if config.Replication != nil {
+		if err := putBucketReplication(ctx, s, s3Client, bucketName, config.Replication); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bucketConfigDiff collects how a bucket we already own differs from the requested configuration.
+type bucketConfigDiff struct {
+	// conflicts describe settings of the bucket that differ from the requested ones
+	conflicts []string
+	// missing applies the requested settings that were never configured on the bucket
+	missing []func() error
+}
+
+func (diff *bucketConfigDiff) conflict(format string, args ...any) {
+	diff.conflicts = append(diff.conflicts, fmt.Sprintf(format, args...))
+}
+
+// checkBucketConfig compares the settings of a bucket we already own with the requested ones.
+// Settings that were never configured on the bucket are applied, so that a creation interrupted
+// before its configuration completed is finished on retry. Conflicting settings return codes.AlreadyExists
+// listing every difference, and leave the bucket untouched.
+// Adopted buckets predate the driver, their lifecycle rules, policy, notifications and replication
+// are compared as well rather than reconciled.
+func checkBucketConfig(ctx context.Context, s *ProvisionerServer, s3Client *s3client.S3Client, bucketName, region string,
+	config *bucketConfig, adopted bool) error {
+	diff := &bucketConfigDiff{}
+
+	if err := diffBucketRegion(ctx, s3Client, bucketName, region, diff); err != nil {
+		return err
+	}
+
+	if config.Versioning != "" {
+		if err := diffBucketVersioning(ctx, s3Client, bucketName, config.Versioning, diff); err != nil {
+			return err
+		}
+	}
+
+	// like the other settings, object lock and default encryption are only compared when the BucketClass
+	// requests them, as object storages may encrypt every bucket by default
+	if config.ObjectLockEnabled {
+		if err := diffObjectLock(ctx, s3Client, bucketName, config.ObjectLockRetention, diff); err != nil {
+			return err
+		}
+	}
+
+	if config.Encryption != nil {
+		if err := diffBucketEncryption(ctx, s3Client, bucketName, config.Encryption, diff); err != nil {
+			return err
+		}
+	}
+
+	if len(config.Tags) > 0 {
+		if err := diffBucketTags(ctx, s3Client, bucketName, config.Tags, diff); err != nil {
+			return err
+		}
+	}
+
+	if len(config.CORSRules) > 0 {
+		if err := diffBucketCors(ctx, s3Client, bucketName, config.CORSRules, diff); err != nil {
+			return err
+		}
+	}
+
+	if adopted {
+		if err := diffAdoptedBucketConfig(ctx, s, s3Client, bucketName, config, diff); err != nil {
+			return err
+		}
+	}
+
+	if len(diff.conflicts) > 0 {
+		klog.V(3).InfoS("Bucket configuration differs from the requested one", "bucketName", bucketName, "conflicts", diff.conflicts)
+		return status.Errorf(codes.AlreadyExists, "Bucket already exists with different parameters: %s: %s",
+			bucketName, strings.Join(diff.conflicts, "; "))
+	}
+
+	if config.BlockPublicAccess && (!adopted || config.BlockPublicAccessExplicit) {
+		if err := blockPublicAccess(ctx, s3Client, bucketName); err != nil {
+			return err
+		}
+	}
+
+	for _, apply := range diff.missing {
+		if err := apply(); err != nil {
+			return err
+		}
+	}
+
+	// the quota cannot be read back, it is only set when the BucketClass requests one
+	if config.Quota > 0 {
+		if err := putBucketQuota(ctx, s3Client, bucketName, config.Quota); err != nil {
+			return err
+		}
+	}
+
+	if adopted {
+		return nil
+	}
+
+	// lifecycle rules, policy, notifications and replication are expected to evolve with the BucketClass,
+	// they are reconciled on the buckets created by the driver rather than compared
+	if config.Lifecycle != nil {
+		if err := putBucketLifecycle(ctx, s3Client, bucketName, config.Lifecycle); err != nil {
+			return err
+		}
+	}
+
+	if config.Policy != "" {
+		if err := putBucketPolicy(ctx, s3Client, bucketName, config.Policy); err != nil {
+			return err
+		}
+	}
+
+	if config.Notification != nil {
+		if err := putBucketNotification(ctx, s3Client, bucketName, config.Notification); err != nil {
+			return err
+		}
+	}
+
+	if config.Replication != nil {
+		if err := putBucketReplication(ctx, s, s3Client, bucketName, config.Replication); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// diffAdoptedBucketConfig compares the settings reconciled on the buckets created by the driver.
+func diffAdoptedBucketConfig(ctx context.Context, s *ProvisionerServer, s3Client *s3client.S3Client, bucketName string,
+	config *bucketConfig, diff *bucketConfigDiff) error {
+	if config.Lifecycle != nil {
+		if err := diffBucketLifecycle(ctx, s3Client, bucketName, config.Lifecycle, diff); err != nil {
+			return err
+		}
+	}
+
+	if config.Policy != "" {
+		if err := diffBucketPolicy(ctx, s3Client, bucketName, config.Policy, diff); err != nil {
+			return err
+		}
+	}
+
+	if config.Notification != nil {
+		if err := diffBucketNotification(ctx, s3Client, bucketName, config.Notification, diff); err != nil {
+			return err
+		}
+	}
+
+	if config.Replication != nil {
+		if err := diffBucketReplication(ctx, s, s3Client, bucketName, config.Replication, diff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// documentsEqual compares S3 configurations through their JSON form, as the SDK types cannot be compared directly.
+// Configurations read back from the object storage must be normalized first, as it adds defaults to them.
+func documentsEqual(a, b any) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
+
+func diffBucketRegion(ctx context.Context, s3Client *s3client.S3Client, bucketName, region string, diff *bucketConfigDiff) error {
+	current, err := s3Client.GetBucketLocation(ctx, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get bucket location", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to get bucket location: %s", bucketName)
+	}
+
+	if region == "" {
+		region = defaultBucketRegion
+	}
+	if current != region {
+		diff.conflict("region is %s, requested %s", current, region)
+	}
+	return nil
+}
+
+func diffBucketVersioning(ctx context.Context, s3Client *s3client.S3Client, bucketName string, versioning s3types.BucketVersioningStatus, diff *bucketConfigDiff) error {
+	current, err := s3Client.GetBucketVersioning(ctx, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get bucket versioning", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to get bucket versioning: %s", bucketName)
+	}
+
+	switch current {
+	case versioning:
+	case "":
+		diff.missing = append(diff.missing, func() error {
+			return putBucketVersioning(ctx, s3Client, bucketName, versioning)
+		})
+	default:
+		diff.conflict("versioning is %s, requested %s", current, versioning)
+	}
+	return nil
+}
+
+func diffObjectLock(ctx context.Context, s3Client *s3client.S3Client, bucketName string, retention *s3types.DefaultRetention, diff *bucketConfigDiff) error {
+	current, err := s3Client.GetObjectLockConfiguration(ctx, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get object lock configuration", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to get object lock configuration: %s", bucketName)
+	}
+
+	// Object Lock can only be enabled when the bucket is created
+	if current == nil || current.ObjectLockEnabled != s3types.ObjectLockEnabledEnabled {
+		diff.conflict("object lock is disabled, requested enabled")
+		return nil
+	}
+
+	var currentRetention *s3types.DefaultRetention
+	if current.Rule != nil {
+		currentRetention = current.Rule.DefaultRetention
+	}
+
+	switch {
+	case retention == nil:
+	case currentRetention == nil:
+		diff.missing = append(diff.missing, func() error {
+			return putObjectLockRetention(ctx, s3Client, bucketName, retention)
+		})
+	case currentRetention.Mode != retention.Mode ||
+		aws.ToInt32(currentRetention.Days) != aws.ToInt32(retention.Days) ||
+		aws.ToInt32(currentRetention.Years) != aws.ToInt32(retention.Years):
+		diff.conflict("object lock retention is %s, requested %s", formatRetention(currentRetention), formatRetention(retention))
+	}
+	return nil
+}
+
+func formatRetention(retention *s3types.DefaultRetention) string {
+	if retention.Years != nil {
+		return fmt.Sprintf("%s for %d years", retention.Mode, aws.ToInt32(retention.Years))
+	}
+	return fmt.Sprintf("%s for %d days", retention.Mode, aws.ToInt32(retention.Days))
+}
+
+func diffBucketEncryption(ctx context.Context, s3Client *s3client.S3Client, bucketName string, encryption *s3types.ServerSideEncryptionByDefault, diff *bucketConfigDiff) error {
+	current, err := s3Client.GetBucketEncryption(ctx, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get bucket encryption", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to get bucket encryption: %s", bucketName)
+	}
+
+	switch {
+	case current == nil:
+		diff.missing = append(diff.missing, func() error {
+			return putBucketEncryption(ctx, s3Client, bucketName, encryption)
+		})
+	case current.SSEAlgorithm != encryption.SSEAlgorithm:
+		diff.conflict("encryption is %s, requested %s", current.SSEAlgorithm, encryption.SSEAlgorithm)
+	case aws.ToString(current.KMSMasterKeyID) != aws.ToString(encryption.KMSMasterKeyID):
+		diff.conflict("encryption KMS key is %q, requested %q", aws.ToString(current.KMSMasterKeyID), aws.ToString(encryption.KMSMasterKeyID))
+	}
+	return nil
+}
+
+// diffBucketTags reports the requested tags set to another value on the bucket.
+// Missing tags are added to the tags already on the bucket.
+func diffBucketTags(ctx context.Context, s3Client *s3client.S3Client, bucketName string, tags map[string]string, diff *bucketConfigDiff) error {
+	current, err := s3Client.GetBucketTagging(ctx, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get bucket tags", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to get bucket tags: %s", bucketName)
+	}
+
+	merged := maps.Clone(current)
+	if merged == nil {
+		merged = map[string]string{}
+	}
+	missing := false
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, exists := current[key]
+		switch {
+		case !exists:
+			merged[key] = tags[key]
+			missing = true
+		case value != tags[key]:
+			diff.conflict("tag %s is %q, requested %q", key, value, tags[key])
+		}
+	}
+
+	if missing {
+		diff.missing = append(diff.missing, func() error {
+			return putBucketTagging(ctx, s3Client, bucketName, merged)
+		})
+	}
+	return nil
+}
+
+func putBucketVersioning(ctx context.Context, s3Client *s3client.S3Client, bucketName string, versioning s3types.BucketVersioningStatus) error {
+	if err := s3Client.PutBucketVersioning(ctx, bucketName, versioning); err != nil {
+		klog.ErrorS(err, "Failed to configure bucket versioning", "bucketName", bucketName, "versioning", versioning)
+		return status.Errorf(codes.Internal, "failed to configure bucket versioning: %s", bucketName)
+	}
+	return nil
+}
+
+func putObjectLockRetention(ctx context.Context, s3Client *s3client.S3Client, bucketName string, retention *s3types.DefaultRetention) error {
+	if err := s3Client.PutObjectLockConfiguration(ctx, bucketName, retention); err != nil {
+		klog.ErrorS(err, "Failed to configure object lock retention", "bucketName", bucketName, "mode", retention.Mode)
+		return status.Errorf(codes.Internal, "failed to configure object lock retention: %s", bucketName)
+	}
+	return nil
+}
+
+func putBucketEncryption(ctx context.Context, s3Client *s3client.S3Client, bucketName string, encryption *s3types.ServerSideEncryptionByDefault) error {
+	if err := s3Client.PutBucketEncryption(ctx, bucketName, encryption); err != nil {
+		klog.ErrorS(err, "Failed to configure bucket encryption", "bucketName", bucketName, "algorithm", encryption.SSEAlgorithm)
+		return status.Errorf(codes.Internal, "failed to configure bucket encryption: %s", bucketName)
+	}
+	return nil
+}
+
+func putBucketTagging(ctx context.Context, s3Client *s3client.S3Client, bucketName string, tags map[string]string) error {
+	if err := s3Client.PutBucketTagging(ctx, bucketName, tags); err != nil {
+		klog.ErrorS(err, "Failed to tag bucket", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to tag bucket: %s", bucketName)
+	}
+	return nil
+}
+
+func putBucketQuota(ctx context.Context, s3Client *s3client.S3Client, bucketName string, quota int64) error {
+	if err := s3Client.PutBucketQuota(ctx, bucketName, quota); err != nil {
+		klog.ErrorS(err, "Failed to set bucket quota", "bucketName", bucketName, "quota", quota)
+		return status.Errorf(codes.Internal, "failed to set bucket quota: %s", bucketName)
+	}
+	return nil
+}
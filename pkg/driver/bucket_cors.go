@@ -0,0 +1,120 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	s3client "github.com/scality/cosi/pkg/util/s3client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultCORSKey = "cors.json"
+	// maximum number of rules accepted in a CORS configuration
+	maxCORSRules = 100
+)
+
+var corsMethods = []string{"GET", "PUT", "POST", "DELETE", "HEAD"}
+
+// resolveCORSConfiguration returns the CORS rules of the ConfigMap referenced by the BucketClass, nil if none.
+// The document uses the S3 CORSConfiguration JSON format, or its YAML equivalent.
+func resolveCORSConfiguration(ctx context.Context, clientset kubernetes.Interface, parameters map[string]string) ([]s3types.CORSRule, error) {
+	if parameters["COSI_BUCKET_CORS_CONFIGMAP_NAME"] == "" {
+		return nil, nil
+	}
+
+	document, err := fetchConfigMapValue(ctx, clientset, parameters, "COSI_BUCKET_CORS_CONFIGMAP", defaultCORSKey, "CORS")
+	if err != nil {
+		return nil, err
+	}
+	return parseCORSConfiguration(document)
+}
+
+func parseCORSConfiguration(document string) ([]s3types.CORSRule, error) {
+	cors := &s3types.CORSConfiguration{}
+	if err := yaml.UnmarshalStrict([]byte(document), cors); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid CORS configuration: %s", err)
+	}
+
+	if len(cors.CORSRules) == 0 || len(cors.CORSRules) > maxCORSRules {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid CORS configuration: between 1 and %d rules are required", maxCORSRules)
+	}
+	for i, rule := range cors.CORSRules {
+		if len(rule.AllowedOrigins) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid CORS configuration: rule %d has no allowed origin", i)
+		}
+		if len(rule.AllowedMethods) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid CORS configuration: rule %d has no allowed method", i)
+		}
+		for _, method := range rule.AllowedMethods {
+			if !slices.Contains(corsMethods, method) {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid CORS configuration: rule %d has an unsupported method %s, must be one of %v",
+					i, method, corsMethods)
+			}
+		}
+		if aws.ToInt32(rule.MaxAgeSeconds) < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid CORS configuration: rule %d has a negative MaxAgeSeconds", i)
+		}
+	}
+
+	return cors.CORSRules, nil
+}
+
+func diffBucketCors(ctx context.Context, s3Client *s3client.S3Client, bucketName string, rules []s3types.CORSRule, diff *bucketConfigDiff) error {
+	current, err := s3Client.GetBucketCors(ctx, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get bucket CORS configuration", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to get bucket CORS configuration: %s", bucketName)
+	}
+
+	switch {
+	case current == nil:
+		diff.missing = append(diff.missing, func() error {
+			return putBucketCors(ctx, s3Client, bucketName, rules)
+		})
+	case !corsRulesEqual(current, rules):
+		diff.conflict("CORS rules differ from the requested ones")
+	}
+	return nil
+}
+
+func corsRulesEqual(a, b []s3types.CORSRule) bool {
+	return slices.EqualFunc(a, b, func(x, y s3types.CORSRule) bool {
+		return aws.ToString(x.ID) == aws.ToString(y.ID) &&
+			aws.ToInt32(x.MaxAgeSeconds) == aws.ToInt32(y.MaxAgeSeconds) &&
+			slices.Equal(x.AllowedOrigins, y.AllowedOrigins) &&
+			slices.Equal(x.AllowedMethods, y.AllowedMethods) &&
+			slices.Equal(x.AllowedHeaders, y.AllowedHeaders) &&
+			slices.Equal(x.ExposeHeaders, y.ExposeHeaders)
+	})
+}
+
+func putBucketCors(ctx context.Context, s3Client *s3client.S3Client, bucketName string, rules []s3types.CORSRule) error {
+	if err := s3Client.PutBucketCors(ctx, bucketName, rules); err != nil {
+		klog.ErrorS(err, "Failed to configure bucket CORS", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to configure bucket CORS: %s", bucketName)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	bucketv1alpha1 "sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	bucketclientset "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned"
+	bucketinformers "sigs.k8s.io/container-object-storage-interface-api/client/informers/externalversions"
+)
+
+// helper method initialized as a variable for testing
+var WatchBucketObjects = watchBucketObjects
+
+const (
+	bucketAccessUIDIndex = "uid"
+	bucketIDIndex        = "bucketID"
+)
+
+// BucketInformer indexes the COSI objects that requests reference without their name,
+// so that they are found without listing every object of the cluster
+type BucketInformer struct {
+	buckets        cache.Indexer
+	bucketAccesses cache.Indexer
+}
+
+// watchBucketObjects starts informers on the Bucket objects, indexed by the bucket ID of their status as
+// templated and adopted buckets have an ID that differs from the object name, and on the BucketAccess objects,
+// indexed by UID as the sidecar names the accounts of the grant requests after the BucketAccess UID.
+func watchBucketObjects(ctx context.Context, bucketClientset bucketclientset.Interface) (*BucketInformer, error) {
+	factory := bucketinformers.NewSharedInformerFactory(bucketClientset, 0)
+	buckets := factory.Objectstorage().V1alpha1().Buckets().Informer()
+	bucketAccesses := factory.Objectstorage().V1alpha1().BucketAccesses().Informer()
+
+	err := buckets.AddIndexers(cache.Indexers{
+		bucketIDIndex: func(obj interface{}) ([]string, error) {
+			bucket, ok := obj.(*bucketv1alpha1.Bucket)
+			if !ok || bucket.Status.BucketID == "" {
+				return nil, nil
+			}
+			return []string{bucket.Status.BucketID}, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = bucketAccesses.AddIndexers(cache.Indexers{
+		bucketAccessUIDIndex: func(obj interface{}) ([]string, error) {
+			object, err := meta.Accessor(obj)
+			if err != nil {
+				return nil, err
+			}
+			return []string{string(object.GetUID())}, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	factory.Start(ctx.Done())
+	for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return nil, fmt.Errorf("failed to sync %v informer", informerType)
+		}
+	}
+
+	klog.V(3).InfoS("Watching COSI bucket objects")
+	return &BucketInformer{buckets: buckets.GetIndexer(), bucketAccesses: bucketAccesses.GetIndexer()}, nil
+}
+
+// bucketsByID returns the Buckets whose status holds the given bucket ID.
+// The returned objects must not be modified.
+func (informer *BucketInformer) bucketsByID(bucketID string) ([]*bucketv1alpha1.Bucket, error) {
+	objects, err := informer.buckets.ByIndex(bucketIDIndex, bucketID)
+	if err != nil {
+		return nil, err
+	}
+	buckets := make([]*bucketv1alpha1.Bucket, 0, len(objects))
+	for _, object := range objects {
+		bucket, ok := object.(*bucketv1alpha1.Bucket)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object in the bucket index: %T", object)
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
+
+// bucketAccessByUID returns the BucketAccess with the given UID, nil if the informer has not seen it.
+// The returned object must not be modified.
+func (informer *BucketInformer) bucketAccessByUID(uid string) (*bucketv1alpha1.BucketAccess, error) {
+	objects, err := informer.bucketAccesses.ByIndex(bucketAccessUIDIndex, uid)
+	if err != nil || len(objects) == 0 {
+		return nil, err
+	}
+	bucketAccess, ok := objects[0].(*bucketv1alpha1.BucketAccess)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object in the bucket access index: %T", objects[0])
+	}
+	return bucketAccess, nil
+}
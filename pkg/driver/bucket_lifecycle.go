@@ -0,0 +1,191 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	s3client "github.com/scality/cosi/pkg/util/s3client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultLifecycleKey    = "lifecycle.json"
+	defaultLifecycleRuleID = "cosi-bucket-lifecycle"
+)
+
+// lifecycle parameters translated into a single rule applying to the whole bucket
+var lifecycleParameters = []string{
+	"COSI_BUCKET_LIFECYCLE_EXPIRATION_DAYS",
+	"COSI_BUCKET_LIFECYCLE_NONCURRENT_EXPIRATION_DAYS",
+	"COSI_BUCKET_LIFECYCLE_ABORT_INCOMPLETE_UPLOAD_DAYS",
+	"COSI_BUCKET_LIFECYCLE_TRANSITION_DAYS",
+	"COSI_BUCKET_LIFECYCLE_TRANSITION_STORAGE_CLASS",
+}
+
+// resolveLifecycleConfiguration returns the lifecycle rules requested by the BucketClass, nil if none.
+// Rules come either from the COSI_BUCKET_LIFECYCLE_* parameters or from a ConfigMap holding a
+// lifecycle configuration document in the S3 JSON format, or its YAML equivalent.
+func resolveLifecycleConfiguration(ctx context.Context, clientset kubernetes.Interface, parameters map[string]string) (*s3types.BucketLifecycleConfiguration, error) {
+	hasParameters := false
+	for _, name := range lifecycleParameters {
+		if parameters[name] != "" {
+			hasParameters = true
+		}
+	}
+
+	if parameters["COSI_BUCKET_LIFECYCLE_CONFIGMAP_NAME"] == "" {
+		if !hasParameters {
+			return nil, nil
+		}
+		return generateLifecycleConfiguration(parameters)
+	}
+
+	if hasParameters {
+		return nil, status.Error(codes.InvalidArgument, "COSI_BUCKET_LIFECYCLE_CONFIGMAP_NAME and COSI_BUCKET_LIFECYCLE_* rule parameters are mutually exclusive")
+	}
+
+	document, err := fetchConfigMapValue(ctx, clientset, parameters, "COSI_BUCKET_LIFECYCLE_CONFIGMAP", defaultLifecycleKey, "lifecycle")
+	if err != nil {
+		return nil, err
+	}
+	return parseLifecycleConfiguration(document)
+}
+
+func generateLifecycleConfiguration(parameters map[string]string) (*s3types.BucketLifecycleConfiguration, error) {
+	rule := s3types.LifecycleRule{
+		ID:     aws.String(defaultLifecycleRuleID),
+		Status: s3types.ExpirationStatusEnabled,
+		Filter: &s3types.LifecycleRuleFilter{Prefix: aws.String("")},
+	}
+
+	if value := parameters["COSI_BUCKET_LIFECYCLE_EXPIRATION_DAYS"]; value != "" {
+		days, err := parsePositiveInt32("COSI_BUCKET_LIFECYCLE_EXPIRATION_DAYS", value)
+		if err != nil {
+			return nil, err
+		}
+		rule.Expiration = &s3types.LifecycleExpiration{Days: aws.Int32(days)}
+	}
+
+	if value := parameters["COSI_BUCKET_LIFECYCLE_NONCURRENT_EXPIRATION_DAYS"]; value != "" {
+		days, err := parsePositiveInt32("COSI_BUCKET_LIFECYCLE_NONCURRENT_EXPIRATION_DAYS", value)
+		if err != nil {
+			return nil, err
+		}
+		rule.NoncurrentVersionExpiration = &s3types.NoncurrentVersionExpiration{NoncurrentDays: aws.Int32(days)}
+	}
+
+	if value := parameters["COSI_BUCKET_LIFECYCLE_ABORT_INCOMPLETE_UPLOAD_DAYS"]; value != "" {
+		days, err := parsePositiveInt32("COSI_BUCKET_LIFECYCLE_ABORT_INCOMPLETE_UPLOAD_DAYS", value)
+		if err != nil {
+			return nil, err
+		}
+		rule.AbortIncompleteMultipartUpload = &s3types.AbortIncompleteMultipartUpload{DaysAfterInitiation: aws.Int32(days)}
+	}
+
+	transitionDays := parameters["COSI_BUCKET_LIFECYCLE_TRANSITION_DAYS"]
+	storageClass := parameters["COSI_BUCKET_LIFECYCLE_TRANSITION_STORAGE_CLASS"]
+	if (transitionDays == "") != (storageClass == "") {
+		return nil, status.Error(codes.InvalidArgument,
+			"COSI_BUCKET_LIFECYCLE_TRANSITION_DAYS and COSI_BUCKET_LIFECYCLE_TRANSITION_STORAGE_CLASS must be set together")
+	}
+	if transitionDays != "" {
+		days, err := parsePositiveInt32("COSI_BUCKET_LIFECYCLE_TRANSITION_DAYS", transitionDays)
+		if err != nil {
+			return nil, err
+		}
+		// the storage class is either an AWS storage class or the name of a Scality location
+		rule.Transitions = []s3types.Transition{{
+			Days:         aws.Int32(days),
+			StorageClass: s3types.TransitionStorageClass(storageClass),
+		}}
+	}
+
+	return &s3types.BucketLifecycleConfiguration{Rules: []s3types.LifecycleRule{rule}}, nil
+}
+
+func parseLifecycleConfiguration(document string) (*s3types.BucketLifecycleConfiguration, error) {
+	lifecycle := &s3types.BucketLifecycleConfiguration{}
+	if err := yaml.UnmarshalStrict([]byte(document), lifecycle); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid lifecycle configuration: %s", err)
+	}
+
+	if len(lifecycle.Rules) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid lifecycle configuration: at least one rule is required")
+	}
+	for i, rule := range lifecycle.Rules {
+		if rule.Status != s3types.ExpirationStatusEnabled && rule.Status != s3types.ExpirationStatusDisabled {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid lifecycle configuration: rule %d status must be Enabled or Disabled", i)
+		}
+		if rule.Prefix == nil && rule.Filter == nil {
+			// S3 rejects rules without a filter, an empty prefix applies the rule to the whole bucket
+			lifecycle.Rules[i].Filter = &s3types.LifecycleRuleFilter{Prefix: aws.String("")}
+		}
+	}
+
+	return lifecycle, nil
+}
+
+func diffBucketLifecycle(ctx context.Context, s3Client *s3client.S3Client, bucketName string, lifecycle *s3types.BucketLifecycleConfiguration, diff *bucketConfigDiff) error {
+	current, err := s3Client.GetBucketLifecycleConfiguration(ctx, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get bucket lifecycle", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to get bucket lifecycle: %s", bucketName)
+	}
+
+	switch {
+	case current == nil:
+		diff.missing = append(diff.missing, func() error {
+			return putBucketLifecycle(ctx, s3Client, bucketName, lifecycle)
+		})
+	case !documentsEqual(normalizeLifecycleRules(current, lifecycle.Rules), normalizeLifecycleRules(lifecycle.Rules, lifecycle.Rules)):
+		diff.conflict("lifecycle rules differ from the requested ones")
+	}
+	return nil
+}
+
+// normalizeLifecycleRules returns a copy of the rules in the form read back from the object storage.
+// Rules applying to the whole bucket are expressed as an empty prefix filter, whether they use the deprecated
+// prefix, an empty prefix or an empty filter, and the IDs generated for requested rules without one are dropped.
+func normalizeLifecycleRules(rules, requested []s3types.LifecycleRule) []s3types.LifecycleRule {
+	normalized := make([]s3types.LifecycleRule, len(rules))
+	for i, rule := range rules {
+		if rule.Filter == nil || *rule.Filter == (s3types.LifecycleRuleFilter{}) {
+			rule.Filter = &s3types.LifecycleRuleFilter{Prefix: aws.String(aws.ToString(rule.Prefix))}
+			rule.Prefix = nil
+		}
+		if i < len(requested) && requested[i].ID == nil {
+			rule.ID = nil
+		}
+		normalized[i] = rule
+	}
+	return normalized
+}
+
+func putBucketLifecycle(ctx context.Context, s3Client *s3client.S3Client, bucketName string, lifecycle *s3types.BucketLifecycleConfiguration) error {
+	if err := s3Client.PutBucketLifecycleConfiguration(ctx, bucketName, lifecycle); err != nil {
+		klog.ErrorS(err, "Failed to configure bucket lifecycle", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to configure bucket lifecycle: %s", bucketName)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	bucketclientset "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned"
+)
+
+const (
+	minBucketNameLength = 3
+	maxBucketNameLength = 63
+)
+
+var bucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]*[a-z0-9]$`)
+
+// BucketNameTemplateData holds the values available to COSI_BUCKET_NAME_TEMPLATE
+type BucketNameTemplateData struct {
+	// Name is the name of the COSI Bucket object
+	Name string
+
+	// bucketClaim returns the reference to the BucketClaim, only fetched when the template uses it
+	bucketClaim func() (*corev1.ObjectReference, error)
+}
+
+// Namespace returns the namespace of the BucketClaim, empty for buckets created without a claim.
+func (data BucketNameTemplateData) Namespace() (string, error) {
+	bucketClaim, err := data.bucketClaim()
+	if err != nil || bucketClaim == nil {
+		return "", err
+	}
+	return bucketClaim.Namespace, nil
+}
+
+// ClaimName returns the name of the BucketClaim, empty for buckets created without a claim.
+func (data BucketNameTemplateData) ClaimName() (string, error) {
+	bucketClaim, err := data.bucketClaim()
+	if err != nil || bucketClaim == nil {
+		return "", err
+	}
+	return bucketClaim.Name, nil
+}
+
+// Hash returns the first n hexadecimal characters of the SHA-256 of the COSI Bucket name.
+// It keeps templated names unique and stable across retries.
+func (data BucketNameTemplateData) Hash(n int) (string, error) {
+	sum := sha256.Sum256([]byte(data.Name))
+	digest := hex.EncodeToString(sum[:])
+	if n < 1 || n > len(digest) {
+		return "", fmt.Errorf("hash length must be between 1 and %d", len(digest))
+	}
+	return digest[:n], nil
+}
+
+// resolveBucketName returns the S3 name of a bucket, rendered from the COSI_BUCKET_NAME_TEMPLATE
+// and COSI_BUCKET_NAME_PREFIX parameters. Without them, the COSI Bucket name is used verbatim.
+// The name only depends on the Bucket object, so that retried requests create the same bucket.
+// The Bucket object is only fetched when the template uses its BucketClaim.
+func resolveBucketName(ctx context.Context, bucketClientset bucketclientset.Interface, informer *BucketInformer, cosiBucketName string, parameters map[string]string) (string, error) {
+	nameTemplate := parameters["COSI_BUCKET_NAME_TEMPLATE"]
+	prefix := parameters["COSI_BUCKET_NAME_PREFIX"]
+	if nameTemplate == "" && prefix == "" {
+		return cosiBucketName, nil
+	}
+
+	var (
+		bucketClaim *corev1.ObjectReference
+		fetchErr    error
+		fetched     bool
+	)
+	data := BucketNameTemplateData{
+		Name: cosiBucketName,
+		bucketClaim: func() (*corev1.ObjectReference, error) {
+			if !fetched {
+				bucketClaim, fetchErr = fetchBucketClaim(ctx, bucketClientset, informer, cosiBucketName)
+				fetched = true
+			}
+			return bucketClaim, fetchErr
+		},
+	}
+
+	name := cosiBucketName
+	if nameTemplate != "" {
+		tmpl, err := template.New("bucket-name").Option("missingkey=error").Parse(nameTemplate)
+		if err != nil {
+			return "", status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_NAME_TEMPLATE: %v", err)
+		}
+
+		var rendered bytes.Buffer
+		if err := tmpl.Execute(&rendered, data); err != nil {
+			if fetchErr != nil {
+				return "", fetchErr
+			}
+			return "", status.Errorf(codes.InvalidArgument, "failed to render COSI_BUCKET_NAME_TEMPLATE: %v", err)
+		}
+		name = rendered.String()
+	}
+
+	name = strings.ToLower(prefix + name)
+	if err := validateBucketName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// validateBucketName enforces the S3 bucket naming rules.
+func validateBucketName(name string) error {
+	if len(name) < minBucketNameLength || len(name) > maxBucketNameLength {
+		return status.Errorf(codes.InvalidArgument, "invalid bucket name %q: must be between %d and %d characters long",
+			name, minBucketNameLength, maxBucketNameLength)
+	}
+	if !bucketNamePattern.MatchString(name) {
+		return status.Errorf(codes.InvalidArgument,
+			"invalid bucket name %q: must contain only lowercase letters, numbers, dots and hyphens, and start and end with a letter or number", name)
+	}
+	if strings.Contains(name, "..") {
+		return status.Errorf(codes.InvalidArgument, "invalid bucket name %q: must not contain two adjacent dots", name)
+	}
+	if net.ParseIP(name) != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid bucket name %q: must not be formatted as an IP address", name)
+	}
+	if strings.HasPrefix(name, "xn--") || strings.HasSuffix(name, "-s3alias") {
+		return status.Errorf(codes.InvalidArgument, "invalid bucket name %q: the xn-- prefix and -s3alias suffix are reserved", name)
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	s3client "github.com/scality/cosi/pkg/util/s3client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultNotificationKey = "notification.json"
+	// notification destinations (Kafka topics, webhooks...) are declared on the object storage
+	// and referenced by name in this ARN form
+	notificationARNPrefix = "arn:scality:bucketnotif:::"
+	defaultNotificationID = "cosi-bucket-notification"
+	// message of the InvalidArgument error returned by the object storage, as by AWS, for destinations
+	// that are not declared in its configuration
+	undeclaredDestinationMessage = "Unable to validate the following destination configurations"
+)
+
+// notification parameters translated into a single queue configuration
+var notificationParameters = []string{
+	"COSI_BUCKET_NOTIFICATION_DESTINATION",
+	"COSI_BUCKET_NOTIFICATION_EVENTS",
+	"COSI_BUCKET_NOTIFICATION_PREFIX",
+	"COSI_BUCKET_NOTIFICATION_SUFFIX",
+}
+
+var defaultNotificationEvents = []s3types.Event{"s3:ObjectCreated:*", "s3:ObjectRemoved:*"}
+
+// resolveBucketNotification returns the event notifications requested by the BucketClass, nil if none.
+// Notifications come either from the COSI_BUCKET_NOTIFICATION_* parameters or from a ConfigMap holding a
+// notification configuration document in the S3 JSON format, or its YAML equivalent.
+func resolveBucketNotification(ctx context.Context, clientset kubernetes.Interface, parameters map[string]string) (*s3types.NotificationConfiguration, error) {
+	hasParameters := false
+	for _, name := range notificationParameters {
+		if parameters[name] != "" {
+			hasParameters = true
+		}
+	}
+
+	if parameters["COSI_BUCKET_NOTIFICATION_CONFIGMAP_NAME"] == "" {
+		if !hasParameters {
+			return nil, nil
+		}
+		return generateBucketNotification(parameters)
+	}
+
+	if hasParameters {
+		return nil, status.Error(codes.InvalidArgument, "COSI_BUCKET_NOTIFICATION_CONFIGMAP_NAME and COSI_BUCKET_NOTIFICATION_* parameters are mutually exclusive")
+	}
+
+	document, err := fetchConfigMapValue(ctx, clientset, parameters, "COSI_BUCKET_NOTIFICATION_CONFIGMAP", defaultNotificationKey, "notification")
+	if err != nil {
+		return nil, err
+	}
+	return parseBucketNotification(document)
+}
+
+func generateBucketNotification(parameters map[string]string) (*s3types.NotificationConfiguration, error) {
+	destination := parameters["COSI_BUCKET_NOTIFICATION_DESTINATION"]
+	if destination == "" {
+		return nil, status.Error(codes.InvalidArgument, "COSI_BUCKET_NOTIFICATION_DESTINATION is required for bucket notifications")
+	}
+	if !strings.HasPrefix(destination, "arn:") {
+		destination = notificationARNPrefix + destination
+	}
+
+	queue := s3types.QueueConfiguration{
+		Id:       aws.String(defaultNotificationID),
+		QueueArn: aws.String(destination),
+		Events:   defaultNotificationEvents,
+	}
+
+	if value := parameters["COSI_BUCKET_NOTIFICATION_EVENTS"]; value != "" {
+		queue.Events = nil
+		for _, event := range strings.Split(value, ",") {
+			queue.Events = append(queue.Events, s3types.Event(strings.TrimSpace(event)))
+		}
+	}
+
+	var filterRules []s3types.FilterRule
+	if prefix := parameters["COSI_BUCKET_NOTIFICATION_PREFIX"]; prefix != "" {
+		filterRules = append(filterRules, s3types.FilterRule{Name: s3types.FilterRuleNamePrefix, Value: aws.String(prefix)})
+	}
+	if suffix := parameters["COSI_BUCKET_NOTIFICATION_SUFFIX"]; suffix != "" {
+		filterRules = append(filterRules, s3types.FilterRule{Name: s3types.FilterRuleNameSuffix, Value: aws.String(suffix)})
+	}
+	if len(filterRules) > 0 {
+		queue.Filter = &s3types.NotificationConfigurationFilter{Key: &s3types.S3KeyFilter{FilterRules: filterRules}}
+	}
+
+	notification := &s3types.NotificationConfiguration{QueueConfigurations: []s3types.QueueConfiguration{queue}}
+	if err := validateBucketNotification(notification); err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+func parseBucketNotification(document string) (*s3types.NotificationConfiguration, error) {
+	notification := &s3types.NotificationConfiguration{}
+	if err := yaml.UnmarshalStrict([]byte(document), notification); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid notification configuration: %s", err)
+	}
+
+	if err := validateBucketNotification(notification); err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+func validateBucketNotification(notification *s3types.NotificationConfiguration) error {
+	// Scality delivers notifications to the destinations of the object storage, as queue configurations only
+	if len(notification.TopicConfigurations) > 0 || len(notification.LambdaFunctionConfigurations) > 0 ||
+		notification.EventBridgeConfiguration != nil {
+		return status.Error(codes.InvalidArgument, "invalid notification configuration: only QueueConfigurations are supported")
+	}
+	if len(notification.QueueConfigurations) == 0 {
+		return status.Error(codes.InvalidArgument, "invalid notification configuration: at least one queue configuration is required")
+	}
+
+	for i, queue := range notification.QueueConfigurations {
+		if aws.ToString(queue.QueueArn) == "" {
+			return status.Errorf(codes.InvalidArgument, "invalid notification configuration: queue configuration %d has no QueueArn", i)
+		}
+		if len(queue.Events) == 0 {
+			return status.Errorf(codes.InvalidArgument, "invalid notification configuration: queue configuration %d has no event", i)
+		}
+		for _, event := range queue.Events {
+			if !strings.HasPrefix(string(event), "s3:") {
+				return status.Errorf(codes.InvalidArgument, "invalid notification configuration: queue configuration %d has an invalid event %s", i, event)
+			}
+		}
+	}
+
+	return nil
+}
+
+func diffBucketNotification(ctx context.Context, s3Client *s3client.S3Client, bucketName string, notification *s3types.NotificationConfiguration, diff *bucketConfigDiff) error {
+	current, err := s3Client.GetBucketNotificationConfiguration(ctx, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get bucket notifications", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to get bucket notifications: %s", bucketName)
+	}
+
+	switch {
+	case current == nil:
+		diff.missing = append(diff.missing, func() error {
+			return putBucketNotification(ctx, s3Client, bucketName, notification)
+		})
+	case !documentsEqual(normalizeBucketNotification(current, notification), normalizeBucketNotification(notification, notification)):
+		diff.conflict("bucket notifications differ from the requested ones")
+	}
+	return nil
+}
+
+// normalizeBucketNotification returns a copy of the notification configuration in a form independent of the way
+// the object storage reads it back: queue configurations are sorted by destination, their events sorted,
+// filter rule names lower-cased, and IDs ignored when a requested configuration has none as the object storage
+// generates them.
+func normalizeBucketNotification(notification, requested *s3types.NotificationConfiguration) *s3types.NotificationConfiguration {
+	generatedIDs := false
+	for _, queue := range requested.QueueConfigurations {
+		if queue.Id == nil {
+			generatedIDs = true
+		}
+	}
+
+	normalized := *notification
+	normalized.QueueConfigurations = make([]s3types.QueueConfiguration, len(notification.QueueConfigurations))
+	for i, queue := range notification.QueueConfigurations {
+		queue.Events = append([]s3types.Event(nil), queue.Events...)
+		sort.Slice(queue.Events, func(a, b int) bool { return queue.Events[a] < queue.Events[b] })
+		if queue.Filter != nil && (queue.Filter.Key == nil || len(queue.Filter.Key.FilterRules) == 0) {
+			queue.Filter = nil
+		}
+		if queue.Filter != nil {
+			filterRules := make([]s3types.FilterRule, len(queue.Filter.Key.FilterRules))
+			for j, filterRule := range queue.Filter.Key.FilterRules {
+				filterRule.Name = s3types.FilterRuleName(strings.ToLower(string(filterRule.Name)))
+				filterRules[j] = filterRule
+			}
+			sort.Slice(filterRules, func(a, b int) bool { return filterRules[a].Name < filterRules[b].Name })
+			queue.Filter = &s3types.NotificationConfigurationFilter{Key: &s3types.S3KeyFilter{FilterRules: filterRules}}
+		}
+		if generatedIDs {
+			queue.Id = nil
+		}
+		normalized.QueueConfigurations[i] = queue
+	}
+	sort.SliceStable(normalized.QueueConfigurations, func(a, b int) bool {
+		return aws.ToString(normalized.QueueConfigurations[a].QueueArn) < aws.ToString(normalized.QueueConfigurations[b].QueueArn)
+	})
+
+	// empty lists are not read back
+	if len(normalized.TopicConfigurations) == 0 {
+		normalized.TopicConfigurations = nil
+	}
+	if len(normalized.LambdaFunctionConfigurations) == 0 {
+		normalized.LambdaFunctionConfigurations = nil
+	}
+	return &normalized
+}
+
+func putBucketNotification(ctx context.Context, s3Client *s3client.S3Client, bucketName string, notification *s3types.NotificationConfiguration) error {
+	if err := s3Client.PutBucketNotificationConfiguration(ctx, bucketName, notification); err != nil {
+		// the object storage rejects invalid configurations and destinations that are not declared in its configuration
+		// with the same error code, destinations are told apart by the message the object storage reserves for them
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "InvalidArgument" {
+			if strings.HasPrefix(apiErr.ErrorMessage(), undeclaredDestinationMessage) {
+				klog.V(3).InfoS("Bucket notification destination rejected by the object storage", "bucketName", bucketName, "message", apiErr.ErrorMessage())
+				return status.Errorf(codes.FailedPrecondition, "bucket notification destination is not configured on the object storage: %s", apiErr.ErrorMessage())
+			}
+			klog.V(3).InfoS("Bucket notification configuration rejected by the object storage", "bucketName", bucketName, "message", apiErr.ErrorMessage())
+			return status.Errorf(codes.InvalidArgument, "bucket notification configuration rejected: %s", apiErr.ErrorMessage())
+		}
+		klog.ErrorS(err, "Failed to configure bucket notifications", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to configure bucket notifications: %s", bucketName)
+	}
+	return nil
+}
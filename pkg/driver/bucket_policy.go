@@ -0,0 +1,144 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"text/template"
+
+	"github.com/aws/smithy-go"
+	s3client "github.com/scality/cosi/pkg/util/s3client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const defaultBucketPolicyKey = "bucket-policy.json"
+
+// BucketPolicyTemplateData holds the values available to bucket policy templates
+type BucketPolicyTemplateData struct {
+	BucketName string
+}
+
+// resolveBucketPolicy returns the bucket policy requested by the BucketClass, empty if none.
+// The policy is a Go template, either inline in COSI_BUCKET_POLICY or stored in a ConfigMap.
+func resolveBucketPolicy(ctx context.Context, clientset kubernetes.Interface, bucketName string, parameters map[string]string) (string, error) {
+	policyTemplate := parameters["COSI_BUCKET_POLICY"]
+
+	if parameters["COSI_BUCKET_POLICY_CONFIGMAP_NAME"] != "" {
+		if policyTemplate != "" {
+			return "", status.Error(codes.InvalidArgument, "COSI_BUCKET_POLICY and COSI_BUCKET_POLICY_CONFIGMAP_NAME are mutually exclusive")
+		}
+
+		var err error
+		policyTemplate, err = fetchConfigMapValue(ctx, clientset, parameters, "COSI_BUCKET_POLICY_CONFIGMAP", defaultBucketPolicyKey, "bucket policy")
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if policyTemplate == "" {
+		return "", nil
+	}
+	return renderBucketPolicy(policyTemplate, BucketPolicyTemplateData{BucketName: bucketName})
+}
+
+// renderBucketPolicy executes a Go template bucket policy and validates the result against the bucket
+func renderBucketPolicy(policyTemplate string, data BucketPolicyTemplateData) (string, error) {
+	tmpl, err := template.New("bucket-policy").Option("missingkey=error").Parse(policyTemplate)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid bucket policy template: %v", err)
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "failed to render bucket policy template: %v", err)
+	}
+
+	if err := validateBucketPolicyDocument(rendered.String(), data.BucketName); err != nil {
+		return "", err
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, rendered.Bytes()); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "bucket policy is not a valid JSON document: %v", err)
+	}
+	return compacted.String(), nil
+}
+
+// validateBucketPolicyDocument applies the IAM policy checks, and requires the Principal of bucket policies
+func validateBucketPolicyDocument(policy, bucketName string) error {
+	if err := ValidatePolicyDocument(policy, bucketName); err != nil {
+		return err
+	}
+
+	var document PolicyDocument
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		return status.Errorf(codes.InvalidArgument, "policy is not a valid JSON document: %v", err)
+	}
+	for i, statement := range document.Statement {
+		if len(statement.Principal) == 0 {
+			return status.Errorf(codes.InvalidArgument, "bucket policy statement %d has no Principal", i)
+		}
+	}
+	return nil
+}
+
+func diffBucketPolicy(ctx context.Context, s3Client *s3client.S3Client, bucketName, policy string, diff *bucketConfigDiff) error {
+	current, err := s3Client.GetBucketPolicy(ctx, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get bucket policy", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to get bucket policy: %s", bucketName)
+	}
+
+	switch {
+	case current == "":
+		diff.missing = append(diff.missing, func() error {
+			return putBucketPolicy(ctx, s3Client, bucketName, policy)
+		})
+	case !jsonDocumentsEqual(current, policy):
+		diff.conflict("bucket policy differs from the requested one")
+	}
+	return nil
+}
+
+// jsonDocumentsEqual compares two JSON documents regardless of their formatting and key order
+func jsonDocumentsEqual(a, b string) bool {
+	var x, y any
+	if json.Unmarshal([]byte(a), &x) != nil || json.Unmarshal([]byte(b), &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func putBucketPolicy(ctx context.Context, s3Client *s3client.S3Client, bucketName, policy string) error {
+	if err := s3Client.PutBucketPolicy(ctx, bucketName, policy); err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "MalformedPolicy" {
+			klog.V(3).InfoS("Bucket policy rejected by the object storage", "bucketName", bucketName, "message", apiErr.ErrorMessage())
+			return status.Errorf(codes.InvalidArgument, "bucket policy rejected: %s", apiErr.ErrorMessage())
+		}
+		klog.ErrorS(err, "Failed to attach bucket policy", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to attach bucket policy: %s", bucketName)
+	}
+	return nil
+}
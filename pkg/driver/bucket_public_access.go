@@ -0,0 +1,114 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	s3client "github.com/scality/cosi/pkg/util/s3client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// parseBlockPublicAccess returns whether public access is blocked on the bucket.
+// COSI_BUCKET_BLOCK_PUBLIC_ACCESS overrides the driver default, "false" being the opt-out for public buckets.
+func parseBlockPublicAccess(parameters map[string]string, driverDefault bool) (bool, error) {
+	value := parameters["COSI_BUCKET_BLOCK_PUBLIC_ACCESS"]
+	if value == "" {
+		return driverDefault, nil
+	}
+
+	block, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_BLOCK_PUBLIC_ACCESS value: %s", value)
+	}
+	return block, nil
+}
+
+// checkPublicBucketPolicy rejects bucket policies allowing anonymous access, which the object storage
+// may not refuse by itself when it does not support public access blocks.
+func checkPublicBucketPolicy(policy string) error {
+	var document PolicyDocument
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		return status.Errorf(codes.InvalidArgument, "policy is not a valid JSON document: %v", err)
+	}
+
+	for i, statement := range document.Statement {
+		if statement.Effect == "Allow" && isPublicPrincipal(statement.Principal) {
+			return status.Errorf(codes.InvalidArgument,
+				"bucket policy statement %d grants public access, set COSI_BUCKET_BLOCK_PUBLIC_ACCESS to false to allow it", i)
+		}
+	}
+	return nil
+}
+
+// isPublicPrincipal reports whether a policy principal is "*" or {"AWS": "*"}.
+func isPublicPrincipal(principal json.RawMessage) bool {
+	var value any
+	if err := json.Unmarshal(principal, &value); err != nil {
+		return false
+	}
+
+	if value == "*" {
+		return true
+	}
+	principals, ok := value.(map[string]any)
+	if !ok {
+		return false
+	}
+	switch arns := principals["AWS"].(type) {
+	case string:
+		return arns == "*"
+	case []any:
+		return slices.Contains(arns, any("*"))
+	}
+	return false
+}
+
+// blockPublicAccess makes a bucket private, and blocks public ACLs and policies when the object storage supports it.
+func blockPublicAccess(ctx context.Context, s3Client *s3client.S3Client, bucketName string) error {
+	if err := s3Client.PutBucketAcl(ctx, bucketName, s3types.BucketCannedACLPrivate); err != nil {
+		klog.ErrorS(err, "Failed to set private bucket ACL", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to set private bucket ACL: %s", bucketName)
+	}
+
+	err := s3Client.PutPublicAccessBlock(ctx, bucketName, &s3types.PublicAccessBlockConfiguration{
+		BlockPublicAcls:       aws.Bool(true),
+		IgnorePublicAcls:      aws.Bool(true),
+		BlockPublicPolicy:     aws.Bool(true),
+		RestrictPublicBuckets: aws.Bool(true),
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotImplemented" {
+			// the private ACL and the policy check still apply
+			klog.V(3).InfoS("Public access block is not supported by the object storage", "bucketName", bucketName)
+			return nil
+		}
+		klog.ErrorS(err, "Failed to block bucket public access", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to block bucket public access: %s", bucketName)
+	}
+	return nil
+}
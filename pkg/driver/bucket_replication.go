@@ -0,0 +1,264 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	s3client "github.com/scality/cosi/pkg/util/s3client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+const (
+	s3ARNPrefix  = "arn:aws:s3:::"
+	iamARNPrefix = "arn:aws:iam::"
+)
+
+// ReplicationDestinationTemplateData holds the values available to COSI_BUCKET_REPLICATION_DESTINATION_BUCKET
+type ReplicationDestinationTemplateData struct {
+	// BucketName is the S3 name of the replicated bucket
+	BucketName string
+}
+
+// bucketReplication describes the replication of a bucket to another site.
+// DestinationParameters locate the object storage provider secret of the destination,
+// they are only set when the driver also creates the destination bucket.
+type bucketReplication struct {
+	Configuration         *s3types.ReplicationConfiguration
+	DestinationBucket     string
+	DestinationParameters map[string]string
+}
+
+// resolveBucketReplication returns the replication requested by the BucketClass, nil if none.
+// The destination bucket is a Go template of the source bucket name, as each bucket needs its own destination.
+// One rule is generated per prefix of COSI_BUCKET_REPLICATION_PREFIXES, or a single rule for the whole bucket.
+func resolveBucketReplication(bucketName string, parameters map[string]string) (*bucketReplication, error) {
+	destination := parameters["COSI_BUCKET_REPLICATION_DESTINATION_BUCKET"]
+	role := parameters["COSI_BUCKET_REPLICATION_ROLE"]
+	if destination == "" {
+		for _, name := range []string{"COSI_BUCKET_REPLICATION_ROLE", "COSI_BUCKET_REPLICATION_STORAGE_CLASS",
+			"COSI_BUCKET_REPLICATION_PREFIXES", "COSI_BUCKET_REPLICATION_DESTINATION_SECRET_NAME"} {
+			if parameters[name] != "" {
+				return nil, status.Errorf(codes.InvalidArgument, "%s requires COSI_BUCKET_REPLICATION_DESTINATION_BUCKET", name)
+			}
+		}
+		return nil, nil
+	}
+
+	destinationBucket, err := renderReplicationDestination(destination, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	secretName := parameters["COSI_BUCKET_REPLICATION_DESTINATION_SECRET_NAME"]
+	if destinationBucket == bucketName && secretName == "" {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"COSI_BUCKET_REPLICATION_DESTINATION_BUCKET renders to the replicated bucket %s, set COSI_BUCKET_REPLICATION_DESTINATION_SECRET_NAME to replicate it to another site", bucketName)
+	}
+
+	// Scality accepts a source and a destination role separated by a comma
+	if role == "" {
+		return nil, status.Error(codes.InvalidArgument, "COSI_BUCKET_REPLICATION_ROLE is required for replication")
+	}
+	roleARNs := splitList(role)
+	for _, roleARN := range roleARNs {
+		if !strings.HasPrefix(roleARN, iamARNPrefix) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_REPLICATION_ROLE value: %s, must be IAM role ARNs", role)
+		}
+	}
+	if len(roleARNs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "COSI_BUCKET_REPLICATION_ROLE is required for replication")
+	}
+
+	prefixes := splitList(parameters["COSI_BUCKET_REPLICATION_PREFIXES"])
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+
+	configuration := &s3types.ReplicationConfiguration{Role: aws.String(strings.Join(roleARNs, ","))}
+	for i, prefix := range prefixes {
+		// rules use the prefix form of the replication configuration, supported by all Scality releases
+		rule := s3types.ReplicationRule{
+			ID:     aws.String(fmt.Sprintf("cosi-replication-%d", i)),
+			Prefix: aws.String(prefix),
+			Status: s3types.ReplicationRuleStatusEnabled,
+			Destination: &s3types.Destination{
+				Bucket: aws.String(bucketARN(destinationBucket)),
+			},
+		}
+		if storageClass := parameters["COSI_BUCKET_REPLICATION_STORAGE_CLASS"]; storageClass != "" {
+			rule.Destination.StorageClass = s3types.StorageClass(storageClass)
+		}
+		configuration.Rules = append(configuration.Rules, rule)
+	}
+
+	replication := &bucketReplication{Configuration: configuration, DestinationBucket: destinationBucket}
+	if secretName != "" {
+		replication.DestinationParameters = map[string]string{
+			"COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME":      secretName,
+			"COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAMESPACE": parameters["COSI_BUCKET_REPLICATION_DESTINATION_SECRET_NAMESPACE"],
+		}
+	}
+
+	return replication, nil
+}
+
+// renderReplicationDestination executes the COSI_BUCKET_REPLICATION_DESTINATION_BUCKET template, a bucket name or ARN.
+// Templates that do not depend on the source bucket name are rejected, as every bucket of the BucketClass
+// would replicate to the same destination.
+func renderReplicationDestination(destinationTemplate, bucketName string) (string, error) {
+	tmpl, err := template.New("replication-destination").Option("missingkey=error").Parse(destinationTemplate)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_REPLICATION_DESTINATION_BUCKET: %v", err)
+	}
+
+	render := func(name string) (string, error) {
+		var rendered bytes.Buffer
+		if err := tmpl.Execute(&rendered, ReplicationDestinationTemplateData{BucketName: name}); err != nil {
+			return "", status.Errorf(codes.InvalidArgument, "failed to render COSI_BUCKET_REPLICATION_DESTINATION_BUCKET: %v", err)
+		}
+		return strings.TrimPrefix(rendered.String(), s3ARNPrefix), nil
+	}
+
+	destination, err := render(bucketName)
+	if err != nil {
+		return "", err
+	}
+	if other, err := render("other-" + bucketName); err != nil || other == destination {
+		return "", status.Errorf(codes.InvalidArgument,
+			"COSI_BUCKET_REPLICATION_DESTINATION_BUCKET must be a template using {{.BucketName}}, buckets cannot share a replication destination: %s", destinationTemplate)
+	}
+
+	if err := validateBucketName(destination); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_REPLICATION_DESTINATION_BUCKET value: %s", status.Convert(err).Message())
+	}
+	return destination, nil
+}
+
+// putBucketReplication creates the versioned destination bucket when requested, then replicates the bucket to it.
+func putBucketReplication(ctx context.Context, s *ProvisionerServer, s3Client *s3client.S3Client, bucketName string, replication *bucketReplication) error {
+	if replication.DestinationParameters != nil {
+		if err := ensureReplicationDestination(ctx, s, replication); err != nil {
+			return err
+		}
+	}
+
+	if err := s3Client.PutBucketReplication(ctx, bucketName, replication.Configuration); err != nil {
+		klog.ErrorS(err, "Failed to configure bucket replication", "bucketName", bucketName, "destination", replication.DestinationBucket)
+		return status.Errorf(codes.Internal, "failed to configure bucket replication: %s", bucketName)
+	}
+	return nil
+}
+
+func diffBucketReplication(ctx context.Context, s *ProvisionerServer, s3Client *s3client.S3Client, bucketName string,
+	replication *bucketReplication, diff *bucketConfigDiff) error {
+	current, err := s3Client.GetBucketReplication(ctx, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get bucket replication", "bucketName", bucketName)
+		return status.Errorf(codes.Internal, "failed to get bucket replication: %s", bucketName)
+	}
+
+	switch {
+	case current == nil:
+		diff.missing = append(diff.missing, func() error {
+			return putBucketReplication(ctx, s, s3Client, bucketName, replication)
+		})
+	case !documentsEqual(normalizeBucketReplication(current, replication.Configuration), normalizeBucketReplication(replication.Configuration, replication.Configuration)):
+		diff.conflict("replication differs from the requested one")
+	}
+	return nil
+}
+
+// normalizeBucketReplication returns a copy of the replication configuration reduced to the settings requested
+// by the BucketClass, sorted by rule ID. The prefix of the rules is read from either the prefix or the filter form,
+// and the defaults the object storage adds to the rules, such as their priority or the replication of delete
+// markers, are ignored, as is the destination storage class when the BucketClass does not request one.
+func normalizeBucketReplication(configuration, requested *s3types.ReplicationConfiguration) *s3types.ReplicationConfiguration {
+	storageClass := len(requested.Rules) > 0 && requested.Rules[0].Destination.StorageClass != ""
+
+	normalized := &s3types.ReplicationConfiguration{Role: configuration.Role}
+	for _, rule := range configuration.Rules {
+		normalizedRule := s3types.ReplicationRule{
+			ID:     rule.ID,
+			Prefix: aws.String(aws.ToString(rule.Prefix)),
+			Status: rule.Status,
+		}
+		if rule.Filter != nil {
+			if rule.Filter.And != nil || rule.Filter.Tag != nil {
+				normalizedRule.Filter = rule.Filter
+			}
+			if rule.Filter.Prefix != nil {
+				normalizedRule.Prefix = rule.Filter.Prefix
+			}
+		}
+		if rule.Destination != nil {
+			normalizedRule.Destination = &s3types.Destination{Bucket: rule.Destination.Bucket}
+			if storageClass {
+				normalizedRule.Destination.StorageClass = rule.Destination.StorageClass
+			}
+		}
+		normalized.Rules = append(normalized.Rules, normalizedRule)
+	}
+	sort.SliceStable(normalized.Rules, func(a, b int) bool {
+		return aws.ToString(normalized.Rules[a].ID) < aws.ToString(normalized.Rules[b].ID)
+	})
+	return normalized
+}
+
+func ensureReplicationDestination(ctx context.Context, s *ProvisionerServer, replication *bucketReplication) error {
+	destinationBucket := replication.DestinationBucket
+
+	destinationClient, destinationParams, err := InitializeClient(ctx, s, replication.DestinationParameters)
+	if err != nil {
+		klog.ErrorS(err, "Failed to initialize replication destination S3 client",
+			"secretName", replication.DestinationParameters["COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME"])
+		return status.Error(codes.Internal, "failed to initialize replication destination S3 client")
+	}
+
+	err = destinationClient.CreateBucket(ctx, destinationBucket, *destinationParams, s3client.BucketOptions{})
+	if err != nil {
+		var bucketOwnedByYou *s3types.BucketAlreadyOwnedByYou
+		if !errors.As(err, &bucketOwnedByYou) {
+			klog.ErrorS(err, "Failed to create replication destination bucket", "destination", destinationBucket)
+			return status.Errorf(codes.Internal, "failed to create replication destination bucket: %s", destinationBucket)
+		}
+		klog.V(3).InfoS("Replication destination bucket already exists", "destination", destinationBucket)
+	}
+
+	// replication requires versioning on both ends
+	return putBucketVersioning(ctx, destinationClient, destinationBucket, s3types.BucketVersioningStatusEnabled)
+}
+
+// splitList returns the trimmed elements of a comma separated parameter, dropping the empty ones
+func splitList(value string) []string {
+	var elements []string
+	for _, element := range strings.Split(value, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
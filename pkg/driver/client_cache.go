@@ -0,0 +1,174 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"sync"
+
+	iamclient "github.com/scality/cosi/pkg/util/iamclient"
+	s3client "github.com/scality/cosi/pkg/util/s3client"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// clientCacheEntry holds the clients built from one version of an object storage provider secret.
+// The IAM client is only built once a request needs it.
+type clientCacheEntry struct {
+	resourceVersion string
+	s3Params        *s3client.S3Params
+	s3Client        *s3client.S3Client
+	iamClient       *iamclient.IAMClient
+}
+
+// ClientCache holds the clients built from each object storage provider secret,
+// so that requests using the same secret share their connection pools.
+// A nil ClientCache caches nothing.
+type ClientCache struct {
+	mu      sync.Mutex
+	entries map[string]*clientCacheEntry
+	// builds serializes the building of the clients of each secret
+	builds map[string]*sync.Mutex
+}
+
+func NewClientCache() *ClientCache {
+	return &ClientCache{
+		entries: map[string]*clientCacheEntry{},
+		builds:  map[string]*sync.Mutex{},
+	}
+}
+
+// lockSecret holds the build lock of a secret until the returned function is called, so that
+// concurrent requests using the same secret build and store a single client.
+func (c *ClientCache) lockSecret(secret *corev1.Secret) func() {
+	if c == nil || secret.ResourceVersion == "" {
+		return func() {}
+	}
+
+	c.mu.Lock()
+	key := secretKey(secret)
+	lock, exists := c.builds[key]
+	if !exists {
+		lock = &sync.Mutex{}
+		c.builds[key] = lock
+	}
+	c.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
+
+// lookup returns a copy of the entry built from the given secret, if the secret did not change since.
+// Secrets without a resourceVersion are never cached, as their changes cannot be detected.
+func (c *ClientCache) lookup(secret *corev1.Secret) (clientCacheEntry, bool) {
+	if c == nil || secret.ResourceVersion == "" {
+		return clientCacheEntry{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[secretKey(secret)]
+	if !exists || entry.resourceVersion != secret.ResourceVersion {
+		return clientCacheEntry{}, false
+	}
+	return *entry, true
+}
+
+// cachedS3Client returns the S3 client built from the current version of a secret, if any
+func (c *ClientCache) cachedS3Client(secret *corev1.Secret) (*s3client.S3Client, *s3client.S3Params, bool) {
+	entry, found := c.lookup(secret)
+	if !found || entry.s3Client == nil {
+		return nil, nil, false
+	}
+	return entry.s3Client, copyS3Params(entry.s3Params), true
+}
+
+// cachedIAMClient returns the IAM client built from the current version of a secret, if any
+func (c *ClientCache) cachedIAMClient(secret *corev1.Secret) (*iamclient.IAMClient, *s3client.S3Params, bool) {
+	entry, found := c.lookup(secret)
+	if !found || entry.iamClient == nil {
+		return nil, nil, false
+	}
+	return entry.iamClient, copyS3Params(entry.s3Params), true
+}
+
+func (c *ClientCache) storeS3Client(secret *corev1.Secret, s3Params *s3client.S3Params, s3Client *s3client.S3Client) {
+	c.store(secret, s3Params, func(entry *clientCacheEntry) { entry.s3Client = s3Client })
+}
+
+func (c *ClientCache) storeIAMClient(secret *corev1.Secret, s3Params *s3client.S3Params, iamClient *iamclient.IAMClient) {
+	c.store(secret, s3Params, func(entry *clientCacheEntry) { entry.iamClient = iamClient })
+}
+
+// store caches a client built from a secret, dropping the clients built from a previous version of it
+func (c *ClientCache) store(secret *corev1.Secret, s3Params *s3client.S3Params, setClient func(entry *clientCacheEntry)) {
+	if c == nil || secret.ResourceVersion == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := secretKey(secret)
+	entry, exists := c.entries[key]
+	if !exists || entry.resourceVersion != secret.ResourceVersion {
+		if exists {
+			entry.closeIdleConnections()
+		}
+		entry = &clientCacheEntry{resourceVersion: secret.ResourceVersion, s3Params: s3Params}
+		c.entries[key] = entry
+	}
+	setClient(entry)
+}
+
+// Invalidate drops the clients of a secret, so that the next request rebuilds them from its current content.
+// The secret is identified by its namespace/name key. Its build lock is dropped as well, so that deleted secrets
+// leave nothing behind: a build in progress completes under the dropped lock, at worst alongside another one.
+func (c *ClientCache) Invalidate(key string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.builds, key)
+	if entry, exists := c.entries[key]; exists {
+		klog.V(4).InfoS("Dropping cached object storage clients", "secret", key)
+		entry.closeIdleConnections()
+		delete(c.entries, key)
+	}
+}
+
+func (entry *clientCacheEntry) closeIdleConnections() {
+	if entry.s3Client != nil {
+		entry.s3Client.CloseIdleConnections()
+	}
+	if entry.iamClient != nil {
+		entry.iamClient.CloseIdleConnections()
+	}
+}
+
+func secretKey(secret *corev1.Secret) string {
+	return secret.Namespace + "/" + secret.Name
+}
+
+// copyS3Params returns a copy of cached parameters, which callers are free to modify
+func copyS3Params(s3Params *s3client.S3Params) *s3client.S3Params {
+	params := *s3Params
+	return &params
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"os"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// fetchConfigMapValue reads a document from the ConfigMap referenced by the <prefix>_NAME, <prefix>_NAMESPACE
+// and <prefix>_KEY parameters. The namespace defaults to the driver namespace and the key to defaultKey.
+// A missing ConfigMap or key is a configuration error and returns codes.InvalidArgument.
+func fetchConfigMapValue(ctx context.Context, clientset kubernetes.Interface, parameters map[string]string,
+	prefix, defaultKey, description string) (string, error) {
+	name := parameters[prefix+"_NAME"]
+	namespace := os.Getenv("POD_NAMESPACE")
+	if parameters[prefix+"_NAMESPACE"] != "" {
+		namespace = parameters[prefix+"_NAMESPACE"]
+	}
+	key := defaultKey
+	if parameters[prefix+"_KEY"] != "" {
+		key = parameters[prefix+"_KEY"]
+	}
+
+	if namespace == "" {
+		klog.ErrorS(nil, "Missing ConfigMap namespace", "configMapName", name, "description", description)
+		return "", status.Errorf(codes.InvalidArgument, "%s ConfigMap namespace is required", description)
+	}
+
+	klog.V(4).InfoS("Fetching ConfigMap", "configMapName", name, "namespace", namespace, "key", key, "description", description)
+	configMap, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", status.Errorf(codes.InvalidArgument, "%s ConfigMap %s/%s not found", description, namespace, name)
+		}
+		klog.ErrorS(err, "Failed to get ConfigMap", "configMapName", name, "namespace", namespace, "description", description)
+		return "", status.Errorf(codes.Internal, "failed to get %s ConfigMap", description)
+	}
+
+	value, exists := configMap.Data[key]
+	if !exists || value == "" {
+		return "", status.Errorf(codes.InvalidArgument, "%s ConfigMap %s/%s has no %s key", description, namespace, name, key)
+	}
+	return value, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"path/filepath"
+	"strings"
+
+	s3client "github.com/scality/cosi/pkg/util/s3client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// MountedCredentialsDir is the directory of the driver pod where the credentials referenced by
+// COSI_S3_CREDENTIALS_NAME are mounted, one subdirectory per name
+const MountedCredentialsDir = "/etc/scality-cosi/credentials"
+
+// parseCredentials returns the credential settings of an object storage provider secret.
+// COSI_S3_CREDENTIALS_SOURCE chooses where the credentials come from:
+//   - secret (default): COSI_S3_ACCESS_KEY_ID, COSI_S3_SECRET_ACCESS_KEY and COSI_S3_SESSION_TOKEN of the secret
+//   - file: the files of the COSI_S3_CREDENTIALS_NAME directory mounted in the driver pod, named like the secret keys
+//   - env: the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN variables of the driver pod
+//
+// With COSI_S3_ROLE_ARN, these credentials are only used to assume the role with STS.
+func parseCredentials(secretData map[string][]byte) (*s3client.S3Params, error) {
+	s3Params := &s3client.S3Params{
+		AccessKey:       string(secretData["COSI_S3_ACCESS_KEY_ID"]),
+		SecretKey:       string(secretData["COSI_S3_SECRET_ACCESS_KEY"]),
+		SessionToken:    string(secretData["COSI_S3_SESSION_TOKEN"]),
+		RoleARN:         string(secretData["COSI_S3_ROLE_ARN"]),
+		RoleSessionName: string(secretData["COSI_S3_ROLE_SESSION_NAME"]),
+	}
+
+	source := strings.ToLower(string(secretData["COSI_S3_CREDENTIALS_SOURCE"]))
+	switch source {
+	case "", "secret":
+		s3Params.CredentialsSource = s3client.CredentialsFromParams
+	case "file":
+		name := string(secretData["COSI_S3_CREDENTIALS_NAME"])
+		if name == "" || name == "." || name == ".." || filepath.Base(name) != name {
+			klog.ErrorS(nil, "Invalid credentials name", "credentialsName", name)
+			return nil, status.Errorf(codes.InvalidArgument, "invalid COSI_S3_CREDENTIALS_NAME value: %q, expected the name of a directory of %s", name, MountedCredentialsDir)
+		}
+		s3Params.CredentialsSource = s3client.CredentialsFromFiles
+		s3Params.CredentialsDir = filepath.Join(MountedCredentialsDir, name)
+	case "env":
+		s3Params.CredentialsSource = s3client.CredentialsFromEnv
+	default:
+		klog.ErrorS(nil, "Invalid credentials source", "credentialsSource", source)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid COSI_S3_CREDENTIALS_SOURCE value: %s, expected one of secret, file, env", source)
+	}
+
+	if s3Params.CredentialsSource != s3client.CredentialsFromParams &&
+		(s3Params.AccessKey != "" || s3Params.SecretKey != "" || s3Params.SessionToken != "") {
+		return nil, status.Errorf(codes.InvalidArgument, "COSI_S3_ACCESS_KEY_ID, COSI_S3_SECRET_ACCESS_KEY and COSI_S3_SESSION_TOKEN cannot be set with COSI_S3_CREDENTIALS_SOURCE %s", source)
+	}
+
+	if s3Params.RoleARN != "" && !strings.HasPrefix(s3Params.RoleARN, "arn:") {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid COSI_S3_ROLE_ARN value: %s", s3Params.RoleARN)
+	}
+	if s3Params.RoleSessionName != "" && s3Params.RoleARN == "" {
+		return nil, status.Error(codes.InvalidArgument, "COSI_S3_ROLE_SESSION_NAME requires COSI_S3_ROLE_ARN")
+	}
+
+	return s3Params, nil
+}
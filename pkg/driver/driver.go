@@ -23,9 +23,23 @@ import (
 	cosiapi "sigs.k8s.io/container-object-storage-interface-spec"
 )
 
+// Options holds the driver-wide settings chosen at startup
+type Options struct {
+	// BlockPublicAccess is the default of COSI_BUCKET_BLOCK_PUBLIC_ACCESS for every BucketClass
+	BlockPublicAccess bool
+	// SecretLabelSelector selects the object storage provider secrets of the driver namespace served from
+	// the informer cache, every secret of the namespace if empty. Other secrets are fetched from the API server
+	// on each request.
+	SecretLabelSelector string
+	// IAMAuthentication enables the IAM authentication type of BucketAccessClasses. The role ARN and STS endpoint
+	// it returns only reach workloads through a sidecar that copies every credential detail into the BucketInfo
+	// secret, which the v0.1.0 sidecar does not: it only copies the access key ID and secret.
+	IAMAuthentication bool
+}
+
 // CreateDriver initializes both the IdentityServer and ProvisionerServer for the COSI driver
-func CreateDriver(ctx context.Context, driverName string) (cosiapi.IdentityServer, cosiapi.ProvisionerServer, error) {
-	provisioner, err := InitProvisionerServer(driverName)
+func CreateDriver(ctx context.Context, driverName string, options Options) (cosiapi.IdentityServer, cosiapi.ProvisionerServer, error) {
+	provisioner, err := InitProvisionerServer(ctx, driverName, options)
 	if err != nil {
 		klog.ErrorS(err, "Provisioner server initialization failed", "driverName", driverName)
 		return nil, nil, err
@@ -0,0 +1,335 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	bucketclientset "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned"
+)
+
+// helper methods initialized as variables for testing
+var ParseAccessMode = parseAccessMode
+var GenerateAccessPolicy = generateAccessPolicy
+var ValidatePolicyDocument = validatePolicyDocument
+var ResolveAccessPolicy = resolveAccessPolicy
+var RenderPolicyTemplate = renderPolicyTemplate
+var GenerateWebIdentityTrustPolicy = generateWebIdentityTrustPolicy
+
+type AccessMode string
+
+const (
+	AccessModeRead      AccessMode = "read"
+	AccessModeWrite     AccessMode = "write"
+	AccessModeReadWrite AccessMode = "readwrite"
+	AccessModeAdmin     AccessMode = "admin"
+
+	defaultAccessMode = AccessModeReadWrite
+	policyVersion     = "2012-10-17"
+
+	defaultPolicyTemplateKey = "policy.json"
+)
+
+// accessModeActions lists, for each access mode, the actions granted on the bucket and on its objects
+var accessModeActions = map[AccessMode]struct {
+	bucket []string
+	object []string
+}{
+	AccessModeRead: {
+		bucket: []string{"s3:ListBucket"},
+		object: []string{"s3:GetObject"},
+	},
+	AccessModeWrite: {
+		object: []string{"s3:PutObject"},
+	},
+	AccessModeReadWrite: {
+		bucket: []string{"s3:ListBucket", "s3:ListBucketMultipartUploads"},
+		object: []string{"s3:GetObject", "s3:PutObject", "s3:DeleteObject", "s3:AbortMultipartUpload", "s3:ListMultipartUploadParts"},
+	},
+	AccessModeAdmin: {
+		bucket: []string{"s3:*"},
+		object: []string{"s3:*"},
+	},
+}
+
+// PolicyDocument is an IAM policy document
+type PolicyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []PolicyStatement `json:"Statement"`
+}
+
+// PolicyStatement is a statement of an IAM or bucket policy document.
+// Action and Resource accept both the string and the list forms allowed by IAM.
+// Principal is only used by bucket policies, and kept as is.
+type PolicyStatement struct {
+	Sid       string          `json:"Sid,omitempty"`
+	Effect    string          `json:"Effect"`
+	Principal json.RawMessage `json:"Principal,omitempty"`
+	Action    stringOrSlice   `json:"Action"`
+	Resource  stringOrSlice   `json:"Resource"`
+}
+
+type stringOrSlice []string
+
+func (s *stringOrSlice) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = []string{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("expected a string or a list of strings: %w", err)
+	}
+	*s = multiple
+	return nil
+}
+
+func parseAccessMode(parameters map[string]string) (AccessMode, error) {
+	value := strings.ToLower(parameters["COSI_ACCESS_MODE"])
+	if value == "" {
+		return defaultAccessMode, nil
+	}
+
+	mode := AccessMode(value)
+	if _, exists := accessModeActions[mode]; !exists {
+		klog.ErrorS(nil, "Invalid access mode", "accessMode", value)
+		return "", status.Errorf(codes.InvalidArgument, "invalid COSI_ACCESS_MODE value: %s, expected one of read, write, readwrite, admin", value)
+	}
+	return mode, nil
+}
+
+// generateAccessPolicy returns the policy document granting the given access mode on a bucket
+func generateAccessPolicy(mode AccessMode, bucketName string) (string, error) {
+	actions, exists := accessModeActions[mode]
+	if !exists {
+		return "", status.Errorf(codes.InvalidArgument, "unsupported access mode: %s", mode)
+	}
+
+	document := PolicyDocument{Version: policyVersion}
+	if len(actions.bucket) > 0 {
+		document.Statement = append(document.Statement, PolicyStatement{
+			Sid:      "BucketAccess",
+			Effect:   "Allow",
+			Action:   actions.bucket,
+			Resource: []string{bucketARN(bucketName)},
+		})
+	}
+	if len(actions.object) > 0 {
+		document.Statement = append(document.Statement, PolicyStatement{
+			Sid:      "ObjectAccess",
+			Effect:   "Allow",
+			Action:   actions.object,
+			Resource: []string{bucketARN(bucketName) + "/*"},
+		})
+	}
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to generate policy for bucket %s", bucketName)
+	}
+
+	policy := string(data)
+	if err := ValidatePolicyDocument(policy, bucketName); err != nil {
+		return "", err
+	}
+	return policy, nil
+}
+
+// PolicyTemplateData holds the variables available to policy templates
+type PolicyTemplateData struct {
+	BucketName  string
+	AccountName string
+	Namespace   string
+}
+
+// resolveAccessPolicy returns the policy document for a bucket access. The policy is rendered from the
+// template referenced by COSI_POLICY_TEMPLATE_CONFIGMAP_NAME when set, and generated from COSI_ACCESS_MODE otherwise.
+func resolveAccessPolicy(ctx context.Context, clientset kubernetes.Interface, bucketClientset bucketclientset.Interface,
+	informer *BucketInformer, parameters map[string]string, bucketName, accountName string) (string, error) {
+	configMapName := parameters["COSI_POLICY_TEMPLATE_CONFIGMAP_NAME"]
+	if configMapName == "" {
+		accessMode, err := ParseAccessMode(parameters)
+		if err != nil {
+			return "", err
+		}
+		klog.V(4).InfoS("Generating access policy", "bucketName", bucketName, "accessMode", accessMode)
+		return GenerateAccessPolicy(accessMode, bucketName)
+	}
+
+	if parameters["COSI_ACCESS_MODE"] != "" {
+		return "", status.Error(codes.InvalidArgument, "COSI_ACCESS_MODE and COSI_POLICY_TEMPLATE_CONFIGMAP_NAME are mutually exclusive")
+	}
+
+	policyTemplate, err := fetchPolicyTemplate(ctx, clientset, parameters)
+	if err != nil {
+		return "", err
+	}
+
+	namespace, err := fetchBucketClaimNamespace(ctx, bucketClientset, informer, bucketName)
+	if err != nil {
+		return "", err
+	}
+
+	klog.V(4).InfoS("Rendering access policy template", "bucketName", bucketName, "configMapName", configMapName)
+	return RenderPolicyTemplate(policyTemplate, PolicyTemplateData{
+		BucketName:  bucketName,
+		AccountName: accountName,
+		Namespace:   namespace,
+	})
+}
+
+func fetchPolicyTemplate(ctx context.Context, clientset kubernetes.Interface, parameters map[string]string) (string, error) {
+	return fetchConfigMapValue(ctx, clientset, parameters, "COSI_POLICY_TEMPLATE_CONFIGMAP", defaultPolicyTemplateKey, "policy template")
+}
+
+func fetchBucketClaimNamespace(ctx context.Context, bucketClientset bucketclientset.Interface, informer *BucketInformer, bucketName string) (string, error) {
+	bucketClaim, err := fetchBucketClaim(ctx, bucketClientset, informer, bucketName)
+	if err != nil || bucketClaim == nil {
+		return "", err
+	}
+	return bucketClaim.Namespace, nil
+}
+
+// renderPolicyTemplate executes a Go template policy and validates the result against the granted bucket
+func renderPolicyTemplate(policyTemplate string, data PolicyTemplateData) (string, error) {
+	tmpl, err := template.New("policy").Option("missingkey=error").Parse(policyTemplate)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid policy template: %v", err)
+	}
+
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "failed to render policy template: %v", err)
+	}
+
+	policy := rendered.String()
+	if err := ValidatePolicyDocument(policy, data.BucketName); err != nil {
+		return "", err
+	}
+
+	// compact the rendered document, as templates are usually indented for readability
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, rendered.Bytes()); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "policy is not a valid JSON document: %v", err)
+	}
+	return compacted.String(), nil
+}
+
+// TrustPolicyDocument is an IAM role trust policy document
+type TrustPolicyDocument struct {
+	Version   string                 `json:"Version"`
+	Statement []TrustPolicyStatement `json:"Statement"`
+}
+
+// TrustPolicyStatement is a statement of an IAM role trust policy document
+type TrustPolicyStatement struct {
+	Effect    string                       `json:"Effect"`
+	Principal map[string]string            `json:"Principal"`
+	Action    string                       `json:"Action"`
+	Condition map[string]map[string]string `json:"Condition"`
+}
+
+// generateWebIdentityTrustPolicy returns a trust policy allowing only the given Kubernetes service account
+// to assume a role with AssumeRoleWithWebIdentity, using tokens issued by the given OIDC provider
+func generateWebIdentityTrustPolicy(providerARN, audience, namespace, serviceAccount string) (string, error) {
+	_, provider, found := strings.Cut(providerARN, ":oidc-provider/")
+	if !found || provider == "" {
+		return "", status.Errorf(codes.InvalidArgument, "invalid OIDC provider ARN: %s", providerARN)
+	}
+	if namespace == "" || serviceAccount == "" {
+		return "", status.Error(codes.InvalidArgument, "service account namespace and name are required")
+	}
+
+	conditions := map[string]string{
+		provider + ":sub": fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount),
+	}
+	if audience != "" {
+		conditions[provider+":aud"] = audience
+	}
+
+	document := TrustPolicyDocument{
+		Version: policyVersion,
+		Statement: []TrustPolicyStatement{
+			{
+				Effect:    "Allow",
+				Principal: map[string]string{"Federated": providerARN},
+				Action:    "sts:AssumeRoleWithWebIdentity",
+				Condition: map[string]map[string]string{"StringEquals": conditions},
+			},
+		},
+	}
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return "", status.Error(codes.Internal, "failed to generate trust policy")
+	}
+	return string(data), nil
+}
+
+// validatePolicyDocument checks that a policy document is well-formed JSON with at least one statement,
+// and that every resource it references belongs to the given bucket
+func validatePolicyDocument(policy, bucketName string) error {
+	var document PolicyDocument
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		return status.Errorf(codes.InvalidArgument, "policy is not a valid JSON document: %v", err)
+	}
+
+	if document.Version == "" {
+		return status.Error(codes.InvalidArgument, "policy Version is required")
+	}
+	if len(document.Statement) == 0 {
+		return status.Error(codes.InvalidArgument, "policy must contain at least one statement")
+	}
+
+	for i, statement := range document.Statement {
+		if statement.Effect != "Allow" && statement.Effect != "Deny" {
+			return status.Errorf(codes.InvalidArgument, "policy statement %d has an invalid Effect: %q", i, statement.Effect)
+		}
+		if len(statement.Action) == 0 {
+			return status.Errorf(codes.InvalidArgument, "policy statement %d has no Action", i)
+		}
+		if len(statement.Resource) == 0 {
+			return status.Errorf(codes.InvalidArgument, "policy statement %d has no Resource", i)
+		}
+		for _, resource := range statement.Resource {
+			if !isBucketResource(resource, bucketName) {
+				return status.Errorf(codes.InvalidArgument, "policy statement %d references a resource outside of bucket %s: %s", i, bucketName, resource)
+			}
+		}
+	}
+	return nil
+}
+
+func isBucketResource(resource, bucketName string) bool {
+	arn := bucketARN(bucketName)
+	return resource == arn || strings.HasPrefix(resource, arn+"/")
+}
+
+func bucketARN(bucketName string) string {
+	return "arn:aws:s3:::" + bucketName
+}
@@ -0,0 +1,362 @@
+package driver_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/scality/cosi/pkg/driver"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	bucketv1alpha1 "sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	bucketfake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
+)
+
+var _ = Describe("ParseAccessMode", func() {
+	It("should default to readwrite when no access mode is provided", func() {
+		mode, err := driver.ParseAccessMode(map[string]string{})
+		Expect(err).To(BeNil())
+		Expect(mode).To(Equal(driver.AccessModeReadWrite))
+	})
+
+	It("should accept access modes regardless of case", func() {
+		mode, err := driver.ParseAccessMode(map[string]string{"COSI_ACCESS_MODE": "Read"})
+		Expect(err).To(BeNil())
+		Expect(mode).To(Equal(driver.AccessModeRead))
+	})
+
+	It("should return InvalidArgument error for an unknown access mode", func() {
+		mode, err := driver.ParseAccessMode(map[string]string{"COSI_ACCESS_MODE": "execute"})
+		Expect(err).To(HaveOccurred())
+		Expect(mode).To(BeEmpty())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("invalid COSI_ACCESS_MODE value: execute"))
+	})
+})
+
+var _ = Describe("GenerateAccessPolicy", func() {
+	var bucketName string
+
+	BeforeEach(func() {
+		bucketName = "test-bucket"
+	})
+
+	parsePolicy := func(policy string) driver.PolicyDocument {
+		var document driver.PolicyDocument
+		Expect(json.Unmarshal([]byte(policy), &document)).To(Succeed())
+		Expect(document.Version).To(Equal("2012-10-17"))
+		return document
+	}
+
+	It("should only allow listing the bucket and reading objects in read mode", func() {
+		policy, err := driver.GenerateAccessPolicy(driver.AccessModeRead, bucketName)
+		Expect(err).To(BeNil())
+
+		document := parsePolicy(policy)
+		Expect(document.Statement).To(HaveLen(2))
+		Expect(document.Statement[0].Effect).To(Equal("Allow"))
+		Expect(document.Statement[0].Action).To(ConsistOf("s3:ListBucket"))
+		Expect(document.Statement[0].Resource).To(ConsistOf("arn:aws:s3:::test-bucket"))
+		Expect(document.Statement[1].Effect).To(Equal("Allow"))
+		Expect(document.Statement[1].Action).To(ConsistOf("s3:GetObject"))
+		Expect(document.Statement[1].Resource).To(ConsistOf("arn:aws:s3:::test-bucket/*"))
+	})
+
+	It("should only allow writing objects in write mode", func() {
+		policy, err := driver.GenerateAccessPolicy(driver.AccessModeWrite, bucketName)
+		Expect(err).To(BeNil())
+
+		document := parsePolicy(policy)
+		Expect(document.Statement).To(HaveLen(1))
+		Expect(document.Statement[0].Action).To(ConsistOf("s3:PutObject"))
+		Expect(document.Statement[0].Resource).To(ConsistOf("arn:aws:s3:::test-bucket/*"))
+	})
+
+	It("should allow reading, writing and deleting objects in readwrite mode", func() {
+		policy, err := driver.GenerateAccessPolicy(driver.AccessModeReadWrite, bucketName)
+		Expect(err).To(BeNil())
+
+		document := parsePolicy(policy)
+		Expect(document.Statement).To(HaveLen(2))
+		Expect(document.Statement[0].Action).To(ContainElement("s3:ListBucket"))
+		Expect(document.Statement[1].Action).To(ContainElements("s3:GetObject", "s3:PutObject", "s3:DeleteObject"))
+		Expect(document.Statement[1].Action).NotTo(ContainElement("s3:*"))
+	})
+
+	It("should allow all actions on the bucket and its objects in admin mode", func() {
+		policy, err := driver.GenerateAccessPolicy(driver.AccessModeAdmin, bucketName)
+		Expect(err).To(BeNil())
+
+		document := parsePolicy(policy)
+		Expect(document.Statement).To(HaveLen(2))
+		Expect(document.Statement[0].Action).To(ConsistOf("s3:*"))
+		Expect(document.Statement[0].Resource).To(ConsistOf("arn:aws:s3:::test-bucket"))
+		Expect(document.Statement[1].Action).To(ConsistOf("s3:*"))
+		Expect(document.Statement[1].Resource).To(ConsistOf("arn:aws:s3:::test-bucket/*"))
+	})
+
+	It("should return InvalidArgument error for an unsupported access mode", func() {
+		policy, err := driver.GenerateAccessPolicy(driver.AccessMode("execute"), bucketName)
+		Expect(err).To(HaveOccurred())
+		Expect(policy).To(BeEmpty())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+})
+
+var _ = Describe("ValidatePolicyDocument", func() {
+	It("should accept a policy using string forms for Action and Resource", func() {
+		policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"s3:DeleteObject","Resource":"arn:aws:s3:::test-bucket/*"}]}`
+		Expect(driver.ValidatePolicyDocument(policy, "test-bucket")).To(Succeed())
+	})
+
+	It("should reject a document that is not valid JSON", func() {
+		err := driver.ValidatePolicyDocument(`{"Version":`, "test-bucket")
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("policy is not a valid JSON document"))
+	})
+
+	It("should reject a document without statements", func() {
+		err := driver.ValidatePolicyDocument(`{"Version":"2012-10-17","Statement":[]}`, "test-bucket")
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("policy must contain at least one statement"))
+	})
+
+	It("should reject a statement with an invalid Effect", func() {
+		policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Maybe","Action":"s3:GetObject","Resource":"arn:aws:s3:::test-bucket/*"}]}`
+		err := driver.ValidatePolicyDocument(policy, "test-bucket")
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("invalid Effect"))
+	})
+
+	It("should reject resources outside of the bucket", func() {
+		policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":["arn:aws:s3:::test-bucket/*","arn:aws:s3:::test-bucket-other/*"]}]}`
+		err := driver.ValidatePolicyDocument(policy, "test-bucket")
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("references a resource outside of bucket test-bucket: arn:aws:s3:::test-bucket-other/*"))
+	})
+
+	It("should reject wildcard resources", func() {
+		policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`
+		err := driver.ValidatePolicyDocument(policy, "test-bucket")
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+})
+
+var _ = Describe("RenderPolicyTemplate", func() {
+	var data driver.PolicyTemplateData
+
+	BeforeEach(func() {
+		data = driver.PolicyTemplateData{
+			BucketName:  "test-bucket",
+			AccountName: "ba-test-access",
+			Namespace:   "test-namespace",
+		}
+	})
+
+	It("should render the template variables and compact the document", func() {
+		policyTemplate := `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": ["s3:GetObject", "s3:PutObject"],
+      "Resource": "arn:aws:s3:::{{.BucketName}}/{{.Namespace}}/{{.AccountName}}/*"
+    }
+  ]
+}`
+		policy, err := driver.RenderPolicyTemplate(policyTemplate, data)
+		Expect(err).To(BeNil())
+		Expect(policy).To(Equal(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":"arn:aws:s3:::test-bucket/test-namespace/ba-test-access/*"}]}`))
+	})
+
+	It("should return InvalidArgument error for a template referencing unknown variables", func() {
+		policy, err := driver.RenderPolicyTemplate(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::{{.Bucket}}/*"}]}`, data)
+		Expect(err).To(HaveOccurred())
+		Expect(policy).To(BeEmpty())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("failed to render policy template"))
+	})
+
+	It("should return InvalidArgument error for a template that cannot be parsed", func() {
+		policy, err := driver.RenderPolicyTemplate(`{{.BucketName`, data)
+		Expect(err).To(HaveOccurred())
+		Expect(policy).To(BeEmpty())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("invalid policy template"))
+	})
+
+	It("should return InvalidArgument error when the rendered policy is not valid JSON", func() {
+		policy, err := driver.RenderPolicyTemplate(`{"Version":"2012-10-17","Statement":[{{.BucketName}}]}`, data)
+		Expect(err).To(HaveOccurred())
+		Expect(policy).To(BeEmpty())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("policy is not a valid JSON document"))
+	})
+
+	It("should return InvalidArgument error when the policy references other buckets", func() {
+		policy, err := driver.RenderPolicyTemplate(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":["arn:aws:s3:::{{.BucketName}}/*","arn:aws:s3:::shared-bucket/*"]}]}`, data)
+		Expect(err).To(HaveOccurred())
+		Expect(policy).To(BeEmpty())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("arn:aws:s3:::shared-bucket/*"))
+	})
+})
+
+var _ = Describe("ResolveAccessPolicy", func() {
+	var (
+		ctx             context.Context
+		clientset       *fake.Clientset
+		bucketClientset *bucketfake.Clientset
+		parameters      map[string]string
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		clientset = fake.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy-templates", Namespace: "cosi-system"},
+			Data: map[string]string{
+				"policy.json":   `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::{{.BucketName}}/{{.Namespace}}/*"}]}`,
+				"deny-delete":   `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"s3:DeleteObject","Resource":"arn:aws:s3:::{{.BucketName}}/*"}]}`,
+				"other-buckets": `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::*"}]}`,
+			},
+		})
+		bucketClientset = bucketfake.NewSimpleClientset(&bucketv1alpha1.Bucket{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-bucket"},
+			Spec: bucketv1alpha1.BucketSpec{
+				BucketClaim: &corev1.ObjectReference{Name: "test-claim", Namespace: "team-a"},
+			},
+		})
+		parameters = map[string]string{
+			"COSI_POLICY_TEMPLATE_CONFIGMAP_NAME":      "policy-templates",
+			"COSI_POLICY_TEMPLATE_CONFIGMAP_NAMESPACE": "cosi-system",
+		}
+	})
+
+	It("should generate the access mode policy when no template is referenced", func() {
+		policy, err := driver.ResolveAccessPolicy(ctx, clientset, bucketClientset, nil, map[string]string{"COSI_ACCESS_MODE": "write"}, "test-bucket", "ba-test-access")
+		Expect(err).To(BeNil())
+
+		expectedPolicy, err := driver.GenerateAccessPolicy(driver.AccessModeWrite, "test-bucket")
+		Expect(err).To(BeNil())
+		Expect(policy).To(Equal(expectedPolicy))
+	})
+
+	It("should render templates through the RenderPolicyTemplate helper", func() {
+		originalRenderPolicyTemplate := driver.RenderPolicyTemplate
+		defer func() { driver.RenderPolicyTemplate = originalRenderPolicyTemplate }()
+
+		var renderedData driver.PolicyTemplateData
+		driver.RenderPolicyTemplate = func(policyTemplate string, data driver.PolicyTemplateData) (string, error) {
+			renderedData = data
+			return "rendered-policy", nil
+		}
+
+		policy, err := driver.ResolveAccessPolicy(ctx, clientset, bucketClientset, nil, parameters, "test-bucket", "ba-test-access")
+		Expect(err).To(BeNil())
+		Expect(policy).To(Equal("rendered-policy"))
+		Expect(renderedData.BucketName).To(Equal("test-bucket"))
+		Expect(renderedData.Namespace).To(Equal("team-a"))
+	})
+
+	It("should render the default key of the referenced ConfigMap with the bucket claim namespace", func() {
+		policy, err := driver.ResolveAccessPolicy(ctx, clientset, bucketClientset, nil, parameters, "test-bucket", "ba-test-access")
+		Expect(err).To(BeNil())
+		Expect(policy).To(ContainSubstring(`"arn:aws:s3:::test-bucket/team-a/*"`))
+	})
+
+	It("should render the key selected by COSI_POLICY_TEMPLATE_CONFIGMAP_KEY", func() {
+		parameters["COSI_POLICY_TEMPLATE_CONFIGMAP_KEY"] = "deny-delete"
+
+		policy, err := driver.ResolveAccessPolicy(ctx, clientset, bucketClientset, nil, parameters, "test-bucket", "ba-test-access")
+		Expect(err).To(BeNil())
+		Expect(policy).To(ContainSubstring(`"Effect":"Deny"`))
+	})
+
+	It("should return InvalidArgument error when the template grants access to other buckets", func() {
+		parameters["COSI_POLICY_TEMPLATE_CONFIGMAP_KEY"] = "other-buckets"
+
+		policy, err := driver.ResolveAccessPolicy(ctx, clientset, bucketClientset, nil, parameters, "test-bucket", "ba-test-access")
+		Expect(err).To(HaveOccurred())
+		Expect(policy).To(BeEmpty())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("should return InvalidArgument error when the ConfigMap key does not exist", func() {
+		parameters["COSI_POLICY_TEMPLATE_CONFIGMAP_KEY"] = "missing-key"
+
+		policy, err := driver.ResolveAccessPolicy(ctx, clientset, bucketClientset, nil, parameters, "test-bucket", "ba-test-access")
+		Expect(err).To(HaveOccurred())
+		Expect(policy).To(BeEmpty())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("policy template ConfigMap cosi-system/policy-templates has no missing-key key"))
+	})
+
+	It("should return InvalidArgument error when the ConfigMap does not exist", func() {
+		parameters["COSI_POLICY_TEMPLATE_CONFIGMAP_NAME"] = "missing-configmap"
+
+		policy, err := driver.ResolveAccessPolicy(ctx, clientset, bucketClientset, nil, parameters, "test-bucket", "ba-test-access")
+		Expect(err).To(HaveOccurred())
+		Expect(policy).To(BeEmpty())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("policy template ConfigMap cosi-system/missing-configmap not found"))
+	})
+
+	It("should return InvalidArgument error when both an access mode and a template are set", func() {
+		parameters["COSI_ACCESS_MODE"] = "read"
+
+		policy, err := driver.ResolveAccessPolicy(ctx, clientset, bucketClientset, nil, parameters, "test-bucket", "ba-test-access")
+		Expect(err).To(HaveOccurred())
+		Expect(policy).To(BeEmpty())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("mutually exclusive"))
+	})
+})
+
+var _ = Describe("GenerateWebIdentityTrustPolicy", func() {
+	var providerARN string
+
+	BeforeEach(func() {
+		providerARN = "arn:aws:iam::123456789012:oidc-provider/oidc.example.com/cluster"
+	})
+
+	It("should only trust the service account through the OIDC provider", func() {
+		policy, err := driver.GenerateWebIdentityTrustPolicy(providerARN, "", "team-a", "analytics")
+		Expect(err).To(BeNil())
+
+		var document driver.TrustPolicyDocument
+		Expect(json.Unmarshal([]byte(policy), &document)).To(Succeed())
+		Expect(document.Statement).To(HaveLen(1))
+		Expect(document.Statement[0].Effect).To(Equal("Allow"))
+		Expect(document.Statement[0].Action).To(Equal("sts:AssumeRoleWithWebIdentity"))
+		Expect(document.Statement[0].Principal).To(Equal(map[string]string{"Federated": providerARN}))
+		Expect(document.Statement[0].Condition).To(Equal(map[string]map[string]string{
+			"StringEquals": {"oidc.example.com/cluster:sub": "system:serviceaccount:team-a:analytics"},
+		}))
+	})
+
+	It("should restrict the token audience when provided", func() {
+		policy, err := driver.GenerateWebIdentityTrustPolicy(providerARN, "sts.scality.com", "team-a", "analytics")
+		Expect(err).To(BeNil())
+		Expect(policy).To(ContainSubstring(`"oidc.example.com/cluster:aud":"sts.scality.com"`))
+	})
+
+	It("should return InvalidArgument error for an invalid OIDC provider ARN", func() {
+		policy, err := driver.GenerateWebIdentityTrustPolicy("oidc.example.com", "", "team-a", "analytics")
+		Expect(err).To(HaveOccurred())
+		Expect(policy).To(BeEmpty())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("invalid OIDC provider ARN"))
+	})
+
+	It("should return InvalidArgument error when the service account is missing", func() {
+		policy, err := driver.GenerateWebIdentityTrustPolicy(providerARN, "", "team-a", "")
+		Expect(err).To(HaveOccurred())
+		Expect(policy).To(BeEmpty())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+})
@@ -19,16 +19,23 @@ import (
 	"context"
 	"errors"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
+	iamclient "github.com/scality/cosi/pkg/util/iamclient"
 	s3client "github.com/scality/cosi/pkg/util/s3client"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
+	bucketv1alpha1 "sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
 	bucketclientset "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned"
 	cosiapi "sigs.k8s.io/container-object-storage-interface-spec"
 )
@@ -38,6 +45,16 @@ type ProvisionerServer struct {
 	Clientset       kubernetes.Interface
 	KubeConfig      *rest.Config
 	BucketClientset bucketclientset.Interface
+	// BlockPublicAccess makes created buckets private unless their BucketClass opts out
+	BlockPublicAccess bool
+	// IAMAuthentication accepts bucket accesses with the IAM authentication type, see Options
+	IAMAuthentication bool
+	// BucketInformer indexes the COSI objects looked up without their name
+	BucketInformer *BucketInformer
+	// ClientCache shares the object storage clients between the requests using the same provider secret
+	ClientCache *ClientCache
+	// SecretInformer serves the watched object storage provider secrets, nil when they are fetched on each request
+	SecretInformer *SecretInformer
 }
 
 var _ cosiapi.ProvisionerServer = &ProvisionerServer{}
@@ -46,8 +63,14 @@ var _ cosiapi.ProvisionerServer = &ProvisionerServer{}
 var InitializeClient = initializeObjectStorageClient
 var FetchSecretInformation = fetchObjectStorageProviderSecretInfo
 var FetchParameters = fetchS3Parameters
+var FetchBucketParameters = fetchBucketParameters
+var InitializeIAMClient = initializeIAMClient
+var FetchBucketAccessServiceAccount = fetchBucketAccessServiceAccount
 
-func InitProvisionerServer(provisioner string) (cosiapi.ProvisionerServer, error) {
+// prefix of the account names generated by the sidecar, followed by the BucketAccess UID
+const bucketAccessAccountPrefix = "ba-"
+
+func InitProvisionerServer(ctx context.Context, provisioner string, options Options) (cosiapi.ProvisionerServer, error) {
 	klog.V(3).InfoS("Initializing ProvisionerServer", "provisioner", provisioner)
 
 	kubeConfig, err := rest.InClusterConfig()
@@ -68,12 +91,36 @@ func InitProvisionerServer(provisioner string) (cosiapi.ProvisionerServer, error
 		return nil, err
 	}
 
+	bucketInformer, err := WatchBucketObjects(ctx, bucketClientset)
+	if err != nil {
+		klog.ErrorS(err, "Failed to watch COSI bucket objects")
+		return nil, err
+	}
+
+	// secrets are only watched in the driver namespace, where the driver is allowed to list them
+	clientCache := NewClientCache()
+	var secretInformer *SecretInformer
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		secretInformer, err = WatchObjectStorageProviderSecrets(ctx, clientset, namespace, options.SecretLabelSelector, clientCache)
+		if err != nil {
+			klog.ErrorS(err, "Failed to watch object storage provider secrets")
+			return nil, err
+		}
+	} else {
+		klog.Warning("POD_NAMESPACE is not set, object storage provider secrets are fetched on each request")
+	}
+
 	klog.V(3).InfoS("Successfully initialized ProvisionerServer", "provisioner", provisioner)
 	return &ProvisionerServer{
-		Provisioner:     provisioner,
-		Clientset:       clientset,
-		KubeConfig:      kubeConfig,
-		BucketClientset: bucketClientset,
+		Provisioner:       provisioner,
+		Clientset:         clientset,
+		KubeConfig:        kubeConfig,
+		BucketClientset:   bucketClientset,
+		BlockPublicAccess: options.BlockPublicAccess,
+		IAMAuthentication: options.IAMAuthentication,
+		BucketInformer:    bucketInformer,
+		ClientCache:       clientCache,
+		SecretInformer:    secretInformer,
 	}, nil
 }
 
@@ -82,27 +129,79 @@ func InitProvisionerServer(provisioner string) (cosiapi.ProvisionerServer, error
 // If the bucket already exists:
 // - AND the parameters are the same, then it MUST return no error
 // - AND the parameters are different, then it MUST return codes.AlreadyExists
+// The S3 bucket is named from COSI_BUCKET_NAME_TEMPLATE when set, and its name is returned as the bucketId
+// With COSI_EXISTING_BUCKET_NAME, a pre-existing bucket owned by the configured credentials is adopted instead,
+// by a single Bucket object
 //
 // Return values
 //
 //	nil -                   Bucket successfully created
-//	codes.AlreadyExists -   Bucket already exists. No more retries
+//	codes.InvalidArgument - Invalid bucket configuration parameters. No more retries
+//	codes.AlreadyExists -   Bucket already exists, or existing bucket already adopted. No more retries
+//	codes.NotFound -        Existing bucket to adopt or Bucket object not found
+//	codes.PermissionDenied - Existing bucket to adopt not owned by the configured credentials
+//	codes.FailedPrecondition - Notification destination not configured on the object storage
 //	non-nil err -           Internal error                                [requeue'd with exponential backoff]
 func (s *ProvisionerServer) DriverCreateBucket(ctx context.Context,
 	req *cosiapi.DriverCreateBucketRequest) (*cosiapi.DriverCreateBucketResponse, error) {
-	bucketName := req.GetName()
+	cosiBucketName := req.GetName()
 	parameters := req.GetParameters()
 
-	klog.V(3).InfoS("Received DriverCreateBucket request", "bucketName", bucketName)
-	klog.V(5).InfoS("Processing DriverCreateBucket", "bucketName", bucketName, "parameters", parameters)
+	klog.V(3).InfoS("Received DriverCreateBucket request", "cosiBucketName", cosiBucketName)
+	klog.V(5).InfoS("Processing DriverCreateBucket", "cosiBucketName", cosiBucketName, "parameters", parameters)
+
+	bucketName, err := resolveExistingBucketName(parameters)
+	if err != nil {
+		klog.ErrorS(err, "Invalid existing bucket name", "cosiBucketName", cosiBucketName)
+		return nil, err
+	}
+	adopted := bucketName != ""
+	if adopted {
+		if err := checkAdoptionConflict(s.BucketInformer, cosiBucketName, bucketName); err != nil {
+			return nil, err
+		}
+	} else {
+		bucketName, err = resolveBucketName(ctx, s.BucketClientset, s.BucketInformer, cosiBucketName, parameters)
+		if err != nil {
+			klog.ErrorS(err, "Invalid bucket name parameters", "cosiBucketName", cosiBucketName)
+			return nil, err
+		}
+	}
+	klog.V(4).InfoS("Resolved bucket name", "cosiBucketName", cosiBucketName, "bucketName", bucketName)
+
+	config, err := resolveBucketConfig(ctx, s.Clientset, bucketName, parameters, s.BlockPublicAccess)
+	if err != nil {
+		klog.ErrorS(err, "Invalid bucket configuration parameters", "bucketName", bucketName)
+		return nil, err
+	}
+
+	// tagging replaces the whole tag set, the tags of adopted buckets are only changed through COSI_BUCKET_TAGS
+	if !adopted {
+		if err := config.addTags(s.bucketTags(cosiBucketName)); err != nil {
+			klog.ErrorS(err, "Invalid bucket tags", "bucketName", bucketName)
+			return nil, err
+		}
+	}
 
-	s3Client, s3Params, err := InitializeClient(ctx, s.Clientset, parameters)
+	s3Client, s3Params, err := InitializeClient(ctx, s, parameters)
 	if err != nil {
 		klog.ErrorS(err, "Failed to initialize object storage provider S3 client", "bucketName", bucketName)
 		return nil, status.Error(codes.Internal, "failed to initialize object storage provider S3 client")
 	}
 
-	err = s3Client.CreateBucket(ctx, bucketName, *s3Params)
+	if adopted {
+		if err := adoptBucket(ctx, s3Client, bucketName); err != nil {
+			return nil, err
+		}
+		if err := checkBucketConfig(ctx, s, s3Client, bucketName, s3Params.Region, config, true); err != nil {
+			return nil, err
+		}
+		return &cosiapi.DriverCreateBucketResponse{
+			BucketId: bucketName,
+		}, nil
+	}
+
+	err = s3Client.CreateBucket(ctx, bucketName, *s3Params, config.createOptions())
 	if err != nil {
 		var bucketAlreadyExists *s3types.BucketAlreadyExists
 		var bucketOwnedByYou *s3types.BucketAlreadyOwnedByYou
@@ -111,6 +210,9 @@ func (s *ProvisionerServer) DriverCreateBucket(ctx context.Context,
 			klog.V(3).InfoS("Bucket already exists", "bucketName", bucketName)
 			return nil, status.Errorf(codes.AlreadyExists, "Bucket already exists: %s", bucketName)
 		} else if errors.As(err, &bucketOwnedByYou) {
+			if err := checkBucketConfig(ctx, s, s3Client, bucketName, s3Params.Region, config, false); err != nil {
+				return nil, err
+			}
 			klog.V(3).InfoS("A bucket with this name exists and is already owned by you: success", "bucketName", bucketName)
 			return &cosiapi.DriverCreateBucketResponse{
 				BucketId: bucketName,
@@ -124,31 +226,46 @@ func (s *ProvisionerServer) DriverCreateBucket(ctx context.Context,
 			return nil, status.Error(codes.Internal, "Failed to create bucket")
 		}
 	}
+
+	if err := applyBucketConfig(ctx, s, s3Client, bucketName, config); err != nil {
+		return nil, err
+	}
 	klog.V(3).InfoS("Successfully created bucket", "bucketName", bucketName)
 	return &cosiapi.DriverCreateBucketResponse{
 		BucketId: bucketName,
 	}, nil
 }
 
-func initializeObjectStorageClient(ctx context.Context, clientset kubernetes.Interface, parameters map[string]string) (*s3client.S3Client, *s3client.S3Params, error) {
+// bucketTags returns the tags identifying the provisioner and the COSI Bucket behind a bucket.
+// The BucketClaim is reachable from the COSI Bucket, it is not tagged so that creating a bucket
+// does not require fetching it.
+func (s *ProvisionerServer) bucketTags(cosiBucketName string) map[string]string {
+	return map[string]string{
+		provisionerTag: s.Provisioner,
+		bucketTag:      cosiBucketName,
+	}
+}
+
+// initializeObjectStorageClient returns the S3 client of the object storage provider secret referenced by the parameters.
+// Clients are cached per secret and reused until the secret changes.
+func initializeObjectStorageClient(ctx context.Context, s *ProvisionerServer, parameters map[string]string) (*s3client.S3Client, *s3client.S3Params, error) {
 	klog.V(3).InfoS("Initializing object storage provider clients", "parameters", parameters)
 
-	ospSecretName, namespace, err := FetchSecretInformation(parameters)
+	ospSecret, err := fetchObjectStorageProviderSecret(ctx, s, parameters)
 	if err != nil {
-		klog.ErrorS(err, "Failed to fetch object storage provider secret info")
 		return nil, nil, err
 	}
 
-	klog.V(4).InfoS("Fetching secret", "secretName", ospSecretName, "namespace", namespace)
-	ospSecret, err := clientset.CoreV1().Secrets(namespace).Get(ctx, ospSecretName, metav1.GetOptions{})
-	if err != nil {
-		klog.ErrorS(err, "Failed to get object store user secret", "secretName", ospSecretName)
-		return nil, nil, status.Error(codes.Internal, "failed to get object store user secret")
+	unlock := s.ClientCache.lockSecret(ospSecret)
+	defer unlock()
+
+	if s3Client, s3Params, found := s.ClientCache.cachedS3Client(ospSecret); found {
+		klog.V(4).InfoS("Reusing cached S3 client", "secretName", ospSecret.Name, "namespace", ospSecret.Namespace)
+		return s3Client, s3Params, nil
 	}
 
-	s3Params, err := FetchParameters(ospSecret.Data)
+	s3Params, err := fetchObjectStorageProviderParameters(ospSecret)
 	if err != nil {
-		klog.ErrorS(err, "Failed to fetch S3 parameters from secret", "secretName", ospSecretName)
 		return nil, nil, err
 	}
 
@@ -157,8 +274,67 @@ func initializeObjectStorageClient(ctx context.Context, clientset kubernetes.Int
 		klog.ErrorS(err, "Failed to create S3 client", "endpoint", s3Params.Endpoint)
 		return nil, nil, status.Error(codes.Internal, "failed to create S3 client")
 	}
+	s.ClientCache.storeS3Client(ospSecret, s3Params, s3Client)
 	klog.V(3).InfoS("Successfully initialized S3 client", "endpoint", s3Params.Endpoint)
-	return s3Client, s3Params, nil // Returning both the client and the params
+	return s3Client, copyS3Params(s3Params), nil
+}
+
+// initializeIAMClient returns the IAM client of the object storage provider secret referenced by the parameters.
+// Clients are cached per secret and reused until the secret changes.
+func initializeIAMClient(ctx context.Context, s *ProvisionerServer, parameters map[string]string) (*iamclient.IAMClient, *s3client.S3Params, error) {
+	klog.V(3).InfoS("Initializing object storage provider IAM client", "parameters", parameters)
+
+	ospSecret, err := fetchObjectStorageProviderSecret(ctx, s, parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unlock := s.ClientCache.lockSecret(ospSecret)
+	defer unlock()
+
+	if iamClient, s3Params, found := s.ClientCache.cachedIAMClient(ospSecret); found {
+		klog.V(4).InfoS("Reusing cached IAM client", "secretName", ospSecret.Name, "namespace", ospSecret.Namespace)
+		return iamClient, s3Params, nil
+	}
+
+	s3Params, err := fetchObjectStorageProviderParameters(ospSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	iamClient, err := iamclient.InitIAMClient(*s3Params)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create IAM client", "endpoint", s3Params.IAMEndpoint)
+		return nil, nil, status.Error(codes.Internal, "failed to create IAM client")
+	}
+	s.ClientCache.storeIAMClient(ospSecret, s3Params, iamClient)
+	klog.V(3).InfoS("Successfully initialized IAM client", "endpoint", s3Params.IAMEndpoint)
+	return iamClient, copyS3Params(s3Params), nil
+}
+
+func fetchObjectStorageProviderSecret(ctx context.Context, s *ProvisionerServer, parameters map[string]string) (*corev1.Secret, error) {
+	ospSecretName, namespace, err := FetchSecretInformation(parameters)
+	if err != nil {
+		klog.ErrorS(err, "Failed to fetch object storage provider secret info")
+		return nil, err
+	}
+
+	klog.V(4).InfoS("Fetching secret", "secretName", ospSecretName, "namespace", namespace)
+	ospSecret, err := getObjectStorageProviderSecret(ctx, s.Clientset, s.SecretInformer, namespace, ospSecretName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get object store user secret", "secretName", ospSecretName)
+		return nil, status.Error(codes.Internal, "failed to get object store user secret")
+	}
+	return ospSecret, nil
+}
+
+func fetchObjectStorageProviderParameters(ospSecret *corev1.Secret) (*s3client.S3Params, error) {
+	s3Params, err := FetchParameters(ospSecret.Data)
+	if err != nil {
+		klog.ErrorS(err, "Failed to fetch S3 parameters from secret", "secretName", ospSecret.Name)
+		return nil, err
+	}
+	return s3Params, nil
 }
 
 func fetchObjectStorageProviderSecretInfo(parameters map[string]string) (string, string, error) {
@@ -181,62 +357,393 @@ func fetchObjectStorageProviderSecretInfo(parameters map[string]string) (string,
 func fetchS3Parameters(secretData map[string][]byte) (*s3client.S3Params, error) {
 	klog.V(5).InfoS("Fetching S3 parameters from secret")
 
-	accessKey := string(secretData["COSI_S3_ACCESS_KEY_ID"])
-	secretKey := string(secretData["COSI_S3_SECRET_ACCESS_KEY"])
 	endpoint := string(secretData["COSI_S3_ENDPOINT"])
 	region := string(secretData["COSI_S3_REGION"])
+	iamEndpoint := string(secretData["COSI_IAM_ENDPOINT"])
+	stsEndpoint := string(secretData["COSI_STS_ENDPOINT"])
 
-	if endpoint == "" || accessKey == "" || secretKey == "" || region == "" {
-		klog.ErrorS(nil, "Missing required S3 parameters", "accessKey", accessKey != "", "secretKey", secretKey != "", "endpoint", endpoint != "", "region", region != "")
-		return nil, status.Error(codes.InvalidArgument, "endpoint, accessKeyID, secretKey and region are required")
+	s3Params, err := parseCredentials(secretData)
+	if err != nil {
+		return nil, err
 	}
 
-	var tlsCert []byte
-	if cert, exists := secretData["COSI_S3_TLS_CERT_SECRET_NAME"]; exists {
-		tlsCert = cert
-	} else {
-		klog.V(5).InfoS("TLS certificate is not provided, proceeding without it")
+	if endpoint == "" || region == "" {
+		klog.ErrorS(nil, "Missing required S3 parameters", "endpoint", endpoint != "", "region", region != "")
+		return nil, status.Error(codes.InvalidArgument, "endpoint and region are required")
+	}
+	// credentials read from files or the environment are only loaded when the clients are built
+	if s3Params.CredentialsSource == s3client.CredentialsFromParams && (s3Params.AccessKey == "" || s3Params.SecretKey == "") {
+		klog.ErrorS(nil, "Missing required S3 credentials", "accessKey", s3Params.AccessKey != "", "secretKey", s3Params.SecretKey != "")
+		return nil, status.Error(codes.InvalidArgument, "accessKeyID and secretKey are required")
 	}
 
-	return &s3client.S3Params{
-		AccessKey: accessKey,
-		SecretKey: secretKey,
-		Endpoint:  endpoint,
-		Region:    region,
-		TLSCert:   tlsCert,
-	}, nil
+	if err := parseTLSConfig(secretData, s3Params); err != nil {
+		return nil, err
+	}
+
+	if iamEndpoint == "" {
+		klog.V(5).InfoS("IAM endpoint is not provided, using the S3 endpoint for IAM operations")
+		iamEndpoint = endpoint
+	}
+	if stsEndpoint == "" {
+		klog.V(5).InfoS("STS endpoint is not provided, using the IAM endpoint for STS operations")
+		stsEndpoint = iamEndpoint
+	}
+
+	s3Params.Endpoint = endpoint
+	s3Params.IAMEndpoint = iamEndpoint
+	s3Params.STSEndpoint = stsEndpoint
+	s3Params.Region = region
+	return s3Params, nil
 }
 
 // DriverDeleteBucket is an idempotent method for deleting buckets
 // It is expected to delete the same bucket given a bucketId
 // If the bucket does not exist, then it MUST return no error
+// When COSI_BUCKET_FORCE_DELETE is set on the BucketClass, the bucket content is purged first
+// Buckets that predate COSI are kept, unless COSI_EXISTING_BUCKET_DELETE allows their deletion
 //
 // Return values
 //
 //	nil -                   Bucket successfully deleted
+//	codes.FailedPrecondition - Bucket is not empty                        [requeue'd with exponential backoff]
+//	codes.DeadlineExceeded - Purge interrupted by the request deadline    [requeue'd with exponential backoff]
 //	non-nil err -           Internal error                                [requeue'd with exponential backoff]
 func (s *ProvisionerServer) DriverDeleteBucket(ctx context.Context,
 	req *cosiapi.DriverDeleteBucketRequest) (*cosiapi.DriverDeleteBucketResponse, error) {
+	bucketName := req.GetBucketId()
+
+	klog.V(3).InfoS("Received DriverDeleteBucket request", "bucketName", bucketName)
+
+	parameters, err := FetchBucketParameters(ctx, s, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to fetch bucket parameters", "bucketName", bucketName)
+		return nil, err
+	}
+
+	if isAdoptedBucket(parameters) {
+		allowed, err := parseExistingBucketDelete(parameters)
+		if err != nil {
+			klog.ErrorS(err, "Invalid existing bucket delete parameter", "bucketName", bucketName)
+			return nil, err
+		}
+		if !allowed {
+			klog.V(3).InfoS("Bucket predates COSI, keeping it", "bucketName", bucketName)
+			return &cosiapi.DriverDeleteBucketResponse{}, nil
+		}
+	}
+
+	s3Client, _, err := InitializeClient(ctx, s, parameters)
+	if err != nil {
+		klog.ErrorS(err, "Failed to initialize object storage provider S3 client", "bucketName", bucketName)
+		return nil, status.Error(codes.Internal, "failed to initialize object storage provider S3 client")
+	}
+
+	forceDelete, err := parseForceDelete(parameters)
+	if err != nil {
+		klog.ErrorS(err, "Invalid force delete parameter", "bucketName", bucketName)
+		return nil, err
+	}
+	if forceDelete {
+		if err := purgeBucket(ctx, s3Client, bucketName); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s3Client.DeleteBucket(ctx, bucketName)
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			switch apiErr.ErrorCode() {
+			case "NoSuchBucket":
+				klog.V(3).InfoS("Bucket does not exist: success", "bucketName", bucketName)
+				return &cosiapi.DriverDeleteBucketResponse{}, nil
+			case "BucketNotEmpty":
+				klog.V(3).InfoS("Bucket is not empty, deletion will be retried", "bucketName", bucketName)
+				return nil, status.Errorf(codes.FailedPrecondition, "Bucket is not empty: %s", bucketName)
+			}
+		}
+
+		var opErr *smithy.OperationError
+		if errors.As(err, &opErr) {
+			klog.V(4).InfoS("AWS operation error", "operation", opErr.OperationName, "message", opErr.Err.Error(), "bucketName", bucketName)
+		}
+		klog.ErrorS(err, "Failed to delete bucket", "bucketName", bucketName)
+		return nil, status.Error(codes.Internal, "Failed to delete bucket")
+	}
+
+	klog.V(3).InfoS("Successfully deleted bucket", "bucketName", bucketName)
+	return &cosiapi.DriverDeleteBucketResponse{}, nil
+}
+
+func parseForceDelete(parameters map[string]string) (bool, error) {
+	value, exists := parameters["COSI_BUCKET_FORCE_DELETE"]
+	if !exists || value == "" {
+		return false, nil
+	}
+
+	forceDelete, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, status.Errorf(codes.InvalidArgument, "invalid COSI_BUCKET_FORCE_DELETE value: %s", value)
+	}
+	return forceDelete, nil
+}
+
+// purgeBucket aborts in-progress multipart uploads and removes all object versions and delete markers.
+// Each step lists the remaining content from scratch, so a purge interrupted by a driver restart
+// or by the request deadline is resumed by the next DriverDeleteBucket call.
+func purgeBucket(ctx context.Context, s3Client *s3client.S3Client, bucketName string) error {
+	klog.V(3).InfoS("Purging bucket content before deletion", "bucketName", bucketName)
 
-	return nil, status.Error(codes.Unimplemented, "DriverCreateBucket: not implemented")
+	if err := s3Client.AbortMultipartUploads(ctx, bucketName); err != nil {
+		return purgeError(ctx, err, bucketName, "failed to abort multipart uploads")
+	}
+
+	if err := s3Client.DeleteObjectVersions(ctx, bucketName); err != nil {
+		return purgeError(ctx, err, bucketName, "failed to delete object versions")
+	}
+
+	klog.V(3).InfoS("Successfully purged bucket content", "bucketName", bucketName)
+	return nil
+}
+
+func purgeError(ctx context.Context, err error, bucketName, message string) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchBucket" {
+		klog.V(3).InfoS("Bucket does not exist, nothing to purge", "bucketName", bucketName)
+		return nil
+	}
+
+	if ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		klog.V(3).InfoS("Bucket purge interrupted, it will resume on retry", "bucketName", bucketName, "reason", err.Error())
+		return status.Errorf(codes.DeadlineExceeded, "bucket purge interrupted: %s", bucketName)
+	}
+
+	klog.ErrorS(err, "Failed to purge bucket", "bucketName", bucketName)
+	return status.Errorf(codes.Internal, "%s: %s", message, bucketName)
 }
 
-// DriverCreateBucketAccess is an idempotent method for creating bucket access
+// fetchBucketAccessServiceAccount finds the BucketAccess behind an account name and returns its service account.
+// The sidecar names accounts after the BucketAccess UID, and forwards neither its namespace nor its service account
+// in the request, the BucketAccess is looked up in the informer index by UID.
+// A BucketAccess the informer has not seen yet returns codes.NotFound, and the sidecar retries the grant.
+func fetchBucketAccessServiceAccount(informer *BucketInformer, accountName string) (string, string, error) {
+	uid := strings.TrimPrefix(accountName, bucketAccessAccountPrefix)
+	klog.V(4).InfoS("Fetching bucket access service account", "accountName", accountName, "uid", uid)
+
+	if informer == nil {
+		klog.ErrorS(nil, "Bucket accesses are not watched", "accountName", accountName)
+		return "", "", status.Error(codes.Internal, "bucket accesses are not watched")
+	}
+
+	bucketAccess, err := informer.bucketAccessByUID(uid)
+	if err != nil {
+		klog.ErrorS(err, "Failed to look up bucket access", "uid", uid)
+		return "", "", status.Error(codes.Internal, "failed to look up bucket access")
+	}
+	if bucketAccess == nil {
+		return "", "", status.Errorf(codes.NotFound, "bucket access not found for account %s", accountName)
+	}
+
+	if bucketAccess.Spec.ServiceAccountName == "" {
+		return "", "", status.Errorf(codes.InvalidArgument, "bucket access %s/%s has no service account", bucketAccess.Namespace, bucketAccess.Name)
+	}
+	return bucketAccess.Namespace, bucketAccess.Spec.ServiceAccountName, nil
+}
+
+// fetchBucket returns the Bucket object behind a bucket ID. Buckets named from a template
+// have an ID that differs from the object name, they are found through the ID in the Bucket status.
+// The returned object must not be modified.
+func fetchBucket(ctx context.Context, bucketClientset bucketclientset.Interface, informer *BucketInformer, bucketID string) (*bucketv1alpha1.Bucket, error) {
+	bucket, err := bucketClientset.ObjectstorageV1alpha1().Buckets().Get(ctx, bucketID, metav1.GetOptions{})
+	if err == nil {
+		return bucket, nil
+	}
+	if !kerrors.IsNotFound(err) {
+		klog.ErrorS(err, "Failed to get bucket object", "bucketID", bucketID)
+		return nil, status.Error(codes.Internal, "failed to get bucket object")
+	}
+
+	if informer != nil {
+		buckets, err := informer.bucketsByID(bucketID)
+		if err != nil {
+			klog.ErrorS(err, "Failed to look up bucket object", "bucketID", bucketID)
+			return nil, status.Error(codes.Internal, "failed to get bucket object")
+		}
+		if len(buckets) > 0 {
+			return buckets[0], nil
+		}
+	}
+
+	klog.ErrorS(nil, "Bucket object not found", "bucketID", bucketID)
+	return nil, status.Errorf(codes.NotFound, "bucket object %s not found", bucketID)
+}
+
+// fetchBucketClaim returns the reference to the BucketClaim of a bucket, nil for buckets created without a claim.
+func fetchBucketClaim(ctx context.Context, bucketClientset bucketclientset.Interface, informer *BucketInformer, bucketID string) (*corev1.ObjectReference, error) {
+	bucket, err := fetchBucket(ctx, bucketClientset, informer, bucketID)
+	if err != nil {
+		return nil, err
+	}
+	return bucket.Spec.BucketClaim, nil
+}
+
+// fetchBucketParameters returns the BucketClass parameters copied onto the Bucket object,
+// as requests that only carry a bucketId need them to locate the object storage provider secret.
+// Statically provisioned buckets report their existing bucket ID as COSI_EXISTING_BUCKET_NAME,
+// so that they are handled as adopted buckets.
+func fetchBucketParameters(ctx context.Context, s *ProvisionerServer, bucketID string) (map[string]string, error) {
+	klog.V(4).InfoS("Fetching bucket parameters", "bucketID", bucketID)
+
+	bucket, err := fetchBucket(ctx, s.BucketClientset, s.BucketInformer, bucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	parameters := bucket.Spec.Parameters
+	if bucket.Spec.ExistingBucketID != "" && parameters["COSI_EXISTING_BUCKET_NAME"] == "" {
+		parameters = make(map[string]string, len(bucket.Spec.Parameters)+1)
+		for key, value := range bucket.Spec.Parameters {
+			parameters[key] = value
+		}
+		parameters["COSI_EXISTING_BUCKET_NAME"] = bucket.Spec.ExistingBucketID
+	}
+
+	klog.V(5).InfoS("Bucket parameters fetched", "bucketID", bucketID, "parameters", parameters)
+	return parameters, nil
+}
+
+// DriverGrantBucketAccess is an idempotent method for creating bucket access
 // It is expected to create the same bucket access given a bucketId, name and protocol
+// Access is granted with a policy restricted to the bucket, selected by the COSI_ACCESS_MODE
+// BucketAccessClass parameter (read, write, readwrite or admin) or rendered from the template
+// stored in the ConfigMap named by COSI_POLICY_TEMPLATE_CONFIGMAP_NAME.
+// With the Key authentication type, the policy is attached to a Vault IAM user named after the request
+// and a new access key is returned. With the IAM authentication type, it is attached to a Vault IAM role
+// that the workload service account assumes through AssumeRoleWithWebIdentity, and no key is issued.
+// The IAM authentication type must be enabled with the IAMAuthentication option, as the role ARN it returns
+// is dropped by sidecars that only copy the access key into the BucketInfo secret.
 //
 // Return values
 //
 //	nil -                   Bucket access successfully created
+//	codes.InvalidArgument - Unsupported authentication type, access mode or policy template
 //	non-nil err -           Internal error                                [requeue'd with exponential backoff]
 func (s *ProvisionerServer) DriverGrantBucketAccess(ctx context.Context,
 	req *cosiapi.DriverGrantBucketAccessRequest) (*cosiapi.DriverGrantBucketAccessResponse, error) {
+	bucketName := req.GetBucketId()
+	userName := req.GetName()
+	authenticationType := req.GetAuthenticationType()
+
+	klog.V(3).InfoS("Received DriverGrantBucketAccess request", "bucketName", bucketName, "userName", userName, "authenticationType", authenticationType)
+	klog.V(5).InfoS("Processing DriverGrantBucketAccess", "bucketName", bucketName, "userName", userName, "parameters", req.GetParameters())
+
+	if bucketName == "" || userName == "" {
+		klog.ErrorS(nil, "Missing bucket ID or bucket access name", "bucketName", bucketName, "userName", userName)
+		return nil, status.Error(codes.InvalidArgument, "bucket ID and bucket access name are required")
+	}
+
+	switch authenticationType {
+	case cosiapi.AuthenticationType_Key:
+	case cosiapi.AuthenticationType_IAM:
+		if !s.IAMAuthentication {
+			klog.ErrorS(nil, "IAM authentication is disabled", "bucketName", bucketName, "userName", userName)
+			return nil, status.Error(codes.InvalidArgument, "IAM authentication is disabled, it requires a COSI sidecar that copies the role ARN into the BucketInfo secret")
+		}
+		if req.GetParameters()["COSI_IAM_OIDC_PROVIDER_ARN"] == "" {
+			klog.ErrorS(nil, "Missing OIDC provider ARN for IAM authentication", "bucketName", bucketName, "userName", userName)
+			return nil, status.Error(codes.InvalidArgument, "COSI_IAM_OIDC_PROVIDER_ARN is required for IAM authentication")
+		}
+	default:
+		klog.ErrorS(nil, "Unsupported authentication type", "authenticationType", authenticationType)
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported authentication type: %s", authenticationType)
+	}
+
+	policy, err := ResolveAccessPolicy(ctx, s.Clientset, s.BucketClientset, s.BucketInformer, req.GetParameters(), bucketName, userName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to resolve access policy", "bucketName", bucketName, "userName", userName)
+		return nil, err
+	}
+
+	parameters, err := FetchBucketParameters(ctx, s, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to fetch bucket parameters", "bucketName", bucketName)
+		return nil, err
+	}
+
+	iamClient, s3Params, err := InitializeIAMClient(ctx, s, parameters)
+	if err != nil {
+		klog.ErrorS(err, "Failed to initialize object storage provider IAM client", "bucketName", bucketName)
+		return nil, status.Error(codes.Internal, "failed to initialize object storage provider IAM client")
+	}
+
+	if authenticationType == cosiapi.AuthenticationType_IAM {
+		return s.grantServiceAccountAccess(ctx, iamClient, s3Params, req.GetParameters(), bucketName, userName, policy)
+	}
+
+	accessKey, err := iamClient.CreateBucketAccess(ctx, userName, bucketName, policy)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create bucket access", "bucketName", bucketName, "userName", userName)
+		return nil, status.Error(codes.Internal, "failed to create bucket access")
+	}
+
+	klog.V(3).InfoS("Successfully granted bucket access", "bucketName", bucketName, "userName", userName)
+	return &cosiapi.DriverGrantBucketAccessResponse{
+		AccountId: userName,
+		Credentials: map[string]*cosiapi.CredentialDetails{
+			"s3": {
+				Secrets: map[string]string{
+					"accessKeyID":     aws.ToString(accessKey.AccessKeyId),
+					"accessSecretKey": aws.ToString(accessKey.SecretAccessKey),
+					"endpoint":        s3Params.Endpoint,
+					"region":          s3Params.Region,
+				},
+			},
+		},
+	}, nil
+}
+
+func (s *ProvisionerServer) grantServiceAccountAccess(ctx context.Context, iamClient *iamclient.IAMClient, s3Params *s3client.S3Params,
+	parameters map[string]string, bucketName, roleName, policy string) (*cosiapi.DriverGrantBucketAccessResponse, error) {
+	namespace, serviceAccount, err := FetchBucketAccessServiceAccount(s.BucketInformer, roleName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to fetch bucket access service account", "roleName", roleName)
+		return nil, err
+	}
+
+	trustPolicy, err := GenerateWebIdentityTrustPolicy(parameters["COSI_IAM_OIDC_PROVIDER_ARN"], parameters["COSI_IAM_OIDC_AUDIENCE"], namespace, serviceAccount)
+	if err != nil {
+		klog.ErrorS(err, "Failed to generate trust policy", "roleName", roleName)
+		return nil, err
+	}
 
-	return nil, status.Error(codes.Unimplemented, "DriverCreateBucket: not implemented")
+	role, err := iamClient.CreateBucketAccessRole(ctx, roleName, bucketName, trustPolicy, policy)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create bucket access role", "bucketName", bucketName, "roleName", roleName)
+		return nil, status.Error(codes.Internal, "failed to create bucket access role")
+	}
+
+	klog.V(3).InfoS("Successfully granted bucket access to service account", "bucketName", bucketName, "roleName", roleName,
+		"namespace", namespace, "serviceAccount", serviceAccount)
+	return &cosiapi.DriverGrantBucketAccessResponse{
+		AccountId: roleName,
+		Credentials: map[string]*cosiapi.CredentialDetails{
+			"s3": {
+				Secrets: map[string]string{
+					"roleArn":     aws.ToString(role.Arn),
+					"stsEndpoint": s3Params.STSEndpoint,
+					"endpoint":    s3Params.Endpoint,
+					"region":      s3Params.Region,
+				},
+			},
+		},
+	}, nil
 }
 
-// DriverDeleteBucketAccess is an idempotent method for deleting bucket access
+// DriverRevokeBucketAccess is an idempotent method for deleting bucket access
 // It is expected to delete the same bucket access given a bucketId and accountId
 // If the bucket access does not exist, then it MUST return no error
+// Both the IAM user and the IAM role named after the accountId are removed
 //
 // Return values
 //
@@ -244,6 +751,39 @@ func (s *ProvisionerServer) DriverGrantBucketAccess(ctx context.Context,
 //	non-nil err -           Internal error                                [requeue'd with exponential backoff]
 func (s *ProvisionerServer) DriverRevokeBucketAccess(ctx context.Context,
 	req *cosiapi.DriverRevokeBucketAccessRequest) (*cosiapi.DriverRevokeBucketAccessResponse, error) {
+	bucketName := req.GetBucketId()
+	userName := req.GetAccountId()
+
+	klog.V(3).InfoS("Received DriverRevokeBucketAccess request", "bucketName", bucketName, "userName", userName)
+
+	if bucketName == "" || userName == "" {
+		klog.ErrorS(nil, "Missing bucket ID or account ID", "bucketName", bucketName, "userName", userName)
+		return nil, status.Error(codes.InvalidArgument, "bucket ID and account ID are required")
+	}
+
+	parameters, err := FetchBucketParameters(ctx, s, bucketName)
+	if err != nil {
+		klog.ErrorS(err, "Failed to fetch bucket parameters", "bucketName", bucketName)
+		return nil, err
+	}
+
+	iamClient, _, err := InitializeIAMClient(ctx, s, parameters)
+	if err != nil {
+		klog.ErrorS(err, "Failed to initialize object storage provider IAM client", "bucketName", bucketName)
+		return nil, status.Error(codes.Internal, "failed to initialize object storage provider IAM client")
+	}
+
+	if err := iamClient.RevokeBucketAccess(ctx, userName); err != nil {
+		klog.ErrorS(err, "Failed to revoke bucket access", "bucketName", bucketName, "userName", userName)
+		return nil, status.Error(codes.Internal, "failed to revoke bucket access")
+	}
+
+	// the authentication type is not part of the request, so the role granted for IAM authentication is removed as well
+	if err := iamClient.RevokeBucketAccessRole(ctx, userName); err != nil {
+		klog.ErrorS(err, "Failed to revoke bucket access role", "bucketName", bucketName, "roleName", userName)
+		return nil, status.Error(codes.Internal, "failed to revoke bucket access")
+	}
 
-	return nil, status.Error(codes.Unimplemented, "DriverCreateBucket: not implemented")
+	klog.V(3).InfoS("Successfully revoked bucket access", "bucketName", bucketName, "userName", userName)
+	return &cosiapi.DriverRevokeBucketAccessResponse{}, nil
 }
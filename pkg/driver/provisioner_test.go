@@ -2,27 +2,76 @@ package driver_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
 	"errors"
+	"fmt"
+	"math/big"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/scality/cosi/pkg/driver"
+	iamclient "github.com/scality/cosi/pkg/util/iamclient"
 	s3client "github.com/scality/cosi/pkg/util/s3client"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	bucketv1alpha1 "sigs.k8s.io/container-object-storage-interface-api/apis/objectstorage/v1alpha1"
+	bucketfake "sigs.k8s.io/container-object-storage-interface-api/client/clientset/versioned/fake"
 	cosiapi "sigs.k8s.io/container-object-storage-interface-spec"
 )
 
 type MockS3Client struct {
-	CreateBucketFunc func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error)
+	CreateBucketFunc                       func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error)
+	DeleteBucketFunc                       func(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error)
+	ListObjectVersionsFunc                 func(ctx context.Context, input *s3.ListObjectVersionsInput, opts ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error)
+	DeleteObjectsFunc                      func(ctx context.Context, input *s3.DeleteObjectsInput, opts ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+	ListMultipartUploadsFunc               func(ctx context.Context, input *s3.ListMultipartUploadsInput, opts ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error)
+	AbortMultipartUploadFunc               func(ctx context.Context, input *s3.AbortMultipartUploadInput, opts ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+	PutBucketVersioningFunc                func(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error)
+	GetBucketVersioningFunc                func(ctx context.Context, input *s3.GetBucketVersioningInput, opts ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error)
+	PutObjectLockConfigurationFunc         func(ctx context.Context, input *s3.PutObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.PutObjectLockConfigurationOutput, error)
+	GetObjectLockConfigurationFunc         func(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error)
+	PutBucketEncryptionFunc                func(ctx context.Context, input *s3.PutBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.PutBucketEncryptionOutput, error)
+	GetBucketEncryptionFunc                func(ctx context.Context, input *s3.GetBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error)
+	PutBucketLifecycleConfigurationFunc    func(ctx context.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error)
+	PutBucketTaggingFunc                   func(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error)
+	PutBucketCorsFunc                      func(ctx context.Context, input *s3.PutBucketCorsInput, opts ...func(*s3.Options)) (*s3.PutBucketCorsOutput, error)
+	GetBucketCorsFunc                      func(ctx context.Context, input *s3.GetBucketCorsInput, opts ...func(*s3.Options)) (*s3.GetBucketCorsOutput, error)
+	PutBucketPolicyFunc                    func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error)
+	PutBucketReplicationFunc               func(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error)
+	PutBucketNotificationConfigurationFunc func(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error)
+	PutBucketAclFunc                       func(ctx context.Context, input *s3.PutBucketAclInput, opts ...func(*s3.Options)) (*s3.PutBucketAclOutput, error)
+	PutPublicAccessBlockFunc               func(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error)
+	HeadBucketFunc                         func(ctx context.Context, input *s3.HeadBucketInput, opts ...func(*s3.Options)) (*s3.HeadBucketOutput, error)
+	ListBucketsFunc                        func(ctx context.Context, input *s3.ListBucketsInput, opts ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
+	GetBucketLocationFunc                  func(ctx context.Context, input *s3.GetBucketLocationInput, opts ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error)
+	GetBucketTaggingFunc                   func(ctx context.Context, input *s3.GetBucketTaggingInput, opts ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error)
+	GetBucketLifecycleConfigurationFunc    func(ctx context.Context, input *s3.GetBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error)
+	GetBucketPolicyFunc                    func(ctx context.Context, input *s3.GetBucketPolicyInput, opts ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error)
+	GetBucketReplicationFunc               func(ctx context.Context, input *s3.GetBucketReplicationInput, opts ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error)
+	GetBucketNotificationConfigurationFunc func(ctx context.Context, input *s3.GetBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketNotificationConfigurationOutput, error)
 }
 
 func (m *MockS3Client) CreateBucket(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
@@ -32,146 +81,3425 @@ func (m *MockS3Client) CreateBucket(ctx context.Context, input *s3.CreateBucketI
 	return &s3.CreateBucketOutput{}, nil
 }
 
+func (m *MockS3Client) DeleteBucket(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+	if m.DeleteBucketFunc != nil {
+		return m.DeleteBucketFunc(ctx, input, opts...)
+	}
+	return &s3.DeleteBucketOutput{}, nil
+}
+
+func (m *MockS3Client) ListObjectVersions(ctx context.Context, input *s3.ListObjectVersionsInput, opts ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error) {
+	if m.ListObjectVersionsFunc != nil {
+		return m.ListObjectVersionsFunc(ctx, input, opts...)
+	}
+	return &s3.ListObjectVersionsOutput{}, nil
+}
+
+func (m *MockS3Client) DeleteObjects(ctx context.Context, input *s3.DeleteObjectsInput, opts ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	if m.DeleteObjectsFunc != nil {
+		return m.DeleteObjectsFunc(ctx, input, opts...)
+	}
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+func (m *MockS3Client) ListMultipartUploads(ctx context.Context, input *s3.ListMultipartUploadsInput, opts ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error) {
+	if m.ListMultipartUploadsFunc != nil {
+		return m.ListMultipartUploadsFunc(ctx, input, opts...)
+	}
+	return &s3.ListMultipartUploadsOutput{}, nil
+}
+
+func (m *MockS3Client) AbortMultipartUpload(ctx context.Context, input *s3.AbortMultipartUploadInput, opts ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	if m.AbortMultipartUploadFunc != nil {
+		return m.AbortMultipartUploadFunc(ctx, input, opts...)
+	}
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketVersioning(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error) {
+	if m.PutBucketVersioningFunc != nil {
+		return m.PutBucketVersioningFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketVersioningOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketVersioning(ctx context.Context, input *s3.GetBucketVersioningInput, opts ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+	if m.GetBucketVersioningFunc != nil {
+		return m.GetBucketVersioningFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketVersioningOutput{}, nil
+}
+
+func (m *MockS3Client) PutObjectLockConfiguration(ctx context.Context, input *s3.PutObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.PutObjectLockConfigurationOutput, error) {
+	if m.PutObjectLockConfigurationFunc != nil {
+		return m.PutObjectLockConfigurationFunc(ctx, input, opts...)
+	}
+	return &s3.PutObjectLockConfigurationOutput{}, nil
+}
+
+func (m *MockS3Client) GetObjectLockConfiguration(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+	if m.GetObjectLockConfigurationFunc != nil {
+		return m.GetObjectLockConfigurationFunc(ctx, input, opts...)
+	}
+	return &s3.GetObjectLockConfigurationOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketEncryption(ctx context.Context, input *s3.PutBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.PutBucketEncryptionOutput, error) {
+	if m.PutBucketEncryptionFunc != nil {
+		return m.PutBucketEncryptionFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketEncryptionOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketEncryption(ctx context.Context, input *s3.GetBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+	if m.GetBucketEncryptionFunc != nil {
+		return m.GetBucketEncryptionFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketEncryptionOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketLifecycleConfiguration(ctx context.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+	if m.PutBucketLifecycleConfigurationFunc != nil {
+		return m.PutBucketLifecycleConfigurationFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketLifecycleConfigurationOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketTagging(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+	if m.PutBucketTaggingFunc != nil {
+		return m.PutBucketTaggingFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketTaggingOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketCors(ctx context.Context, input *s3.PutBucketCorsInput, opts ...func(*s3.Options)) (*s3.PutBucketCorsOutput, error) {
+	if m.PutBucketCorsFunc != nil {
+		return m.PutBucketCorsFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketCorsOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketCors(ctx context.Context, input *s3.GetBucketCorsInput, opts ...func(*s3.Options)) (*s3.GetBucketCorsOutput, error) {
+	if m.GetBucketCorsFunc != nil {
+		return m.GetBucketCorsFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketCorsOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketPolicy(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+	if m.PutBucketPolicyFunc != nil {
+		return m.PutBucketPolicyFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketPolicyOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketReplication(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error) {
+	if m.PutBucketReplicationFunc != nil {
+		return m.PutBucketReplicationFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketReplicationOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketNotificationConfiguration(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error) {
+	if m.PutBucketNotificationConfigurationFunc != nil {
+		return m.PutBucketNotificationConfigurationFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketNotificationConfigurationOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketAcl(ctx context.Context, input *s3.PutBucketAclInput, opts ...func(*s3.Options)) (*s3.PutBucketAclOutput, error) {
+	if m.PutBucketAclFunc != nil {
+		return m.PutBucketAclFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketAclOutput{}, nil
+}
+
+func (m *MockS3Client) PutPublicAccessBlock(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error) {
+	if m.PutPublicAccessBlockFunc != nil {
+		return m.PutPublicAccessBlockFunc(ctx, input, opts...)
+	}
+	return &s3.PutPublicAccessBlockOutput{}, nil
+}
+
+func (m *MockS3Client) HeadBucket(ctx context.Context, input *s3.HeadBucketInput, opts ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+	if m.HeadBucketFunc != nil {
+		return m.HeadBucketFunc(ctx, input, opts...)
+	}
+	return &s3.HeadBucketOutput{}, nil
+}
+
+func (m *MockS3Client) ListBuckets(ctx context.Context, input *s3.ListBucketsInput, opts ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+	if m.ListBucketsFunc != nil {
+		return m.ListBucketsFunc(ctx, input, opts...)
+	}
+	return &s3.ListBucketsOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketLocation(ctx context.Context, input *s3.GetBucketLocationInput, opts ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+	if m.GetBucketLocationFunc != nil {
+		return m.GetBucketLocationFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketLocationOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketTagging(ctx context.Context, input *s3.GetBucketTaggingInput, opts ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+	if m.GetBucketTaggingFunc != nil {
+		return m.GetBucketTaggingFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketTaggingOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketLifecycleConfiguration(ctx context.Context, input *s3.GetBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+	if m.GetBucketLifecycleConfigurationFunc != nil {
+		return m.GetBucketLifecycleConfigurationFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketLifecycleConfigurationOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketPolicy(ctx context.Context, input *s3.GetBucketPolicyInput, opts ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error) {
+	if m.GetBucketPolicyFunc != nil {
+		return m.GetBucketPolicyFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketPolicyOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketReplication(ctx context.Context, input *s3.GetBucketReplicationInput, opts ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error) {
+	if m.GetBucketReplicationFunc != nil {
+		return m.GetBucketReplicationFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketReplicationOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketNotificationConfiguration(ctx context.Context, input *s3.GetBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketNotificationConfigurationOutput, error) {
+	if m.GetBucketNotificationConfigurationFunc != nil {
+		return m.GetBucketNotificationConfigurationFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketNotificationConfigurationOutput{}, nil
+}
+
+type MockQuotaClient struct {
+	PutBucketQuotaFunc func(ctx context.Context, bucketName string, quota int64) error
+}
+
+func (m *MockQuotaClient) PutBucketQuota(ctx context.Context, bucketName string, quota int64) error {
+	if m.PutBucketQuotaFunc != nil {
+		return m.PutBucketQuotaFunc(ctx, bucketName, quota)
+	}
+	return nil
+}
+
+type MockIAMClient struct {
+	CreateUserFunc               func(ctx context.Context, input *iam.CreateUserInput, opts ...func(*iam.Options)) (*iam.CreateUserOutput, error)
+	PutUserPolicyFunc            func(ctx context.Context, input *iam.PutUserPolicyInput, opts ...func(*iam.Options)) (*iam.PutUserPolicyOutput, error)
+	ListAccessKeysFunc           func(ctx context.Context, input *iam.ListAccessKeysInput, opts ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error)
+	CreateAccessKeyFunc          func(ctx context.Context, input *iam.CreateAccessKeyInput, opts ...func(*iam.Options)) (*iam.CreateAccessKeyOutput, error)
+	DeleteAccessKeyFunc          func(ctx context.Context, input *iam.DeleteAccessKeyInput, opts ...func(*iam.Options)) (*iam.DeleteAccessKeyOutput, error)
+	ListUserPoliciesFunc         func(ctx context.Context, input *iam.ListUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListUserPoliciesOutput, error)
+	DeleteUserPolicyFunc         func(ctx context.Context, input *iam.DeleteUserPolicyInput, opts ...func(*iam.Options)) (*iam.DeleteUserPolicyOutput, error)
+	ListAttachedUserPoliciesFunc func(ctx context.Context, input *iam.ListAttachedUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListAttachedUserPoliciesOutput, error)
+	DetachUserPolicyFunc         func(ctx context.Context, input *iam.DetachUserPolicyInput, opts ...func(*iam.Options)) (*iam.DetachUserPolicyOutput, error)
+	DeleteUserFunc               func(ctx context.Context, input *iam.DeleteUserInput, opts ...func(*iam.Options)) (*iam.DeleteUserOutput, error)
+	CreateRoleFunc               func(ctx context.Context, input *iam.CreateRoleInput, opts ...func(*iam.Options)) (*iam.CreateRoleOutput, error)
+	GetRoleFunc                  func(ctx context.Context, input *iam.GetRoleInput, opts ...func(*iam.Options)) (*iam.GetRoleOutput, error)
+	UpdateAssumeRolePolicyFunc   func(ctx context.Context, input *iam.UpdateAssumeRolePolicyInput, opts ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error)
+	PutRolePolicyFunc            func(ctx context.Context, input *iam.PutRolePolicyInput, opts ...func(*iam.Options)) (*iam.PutRolePolicyOutput, error)
+	ListRolePoliciesFunc         func(ctx context.Context, input *iam.ListRolePoliciesInput, opts ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error)
+	DeleteRolePolicyFunc         func(ctx context.Context, input *iam.DeleteRolePolicyInput, opts ...func(*iam.Options)) (*iam.DeleteRolePolicyOutput, error)
+	ListAttachedRolePoliciesFunc func(ctx context.Context, input *iam.ListAttachedRolePoliciesInput, opts ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error)
+	DetachRolePolicyFunc         func(ctx context.Context, input *iam.DetachRolePolicyInput, opts ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error)
+	DeleteRoleFunc               func(ctx context.Context, input *iam.DeleteRoleInput, opts ...func(*iam.Options)) (*iam.DeleteRoleOutput, error)
+}
+
+func (m *MockIAMClient) CreateUser(ctx context.Context, input *iam.CreateUserInput, opts ...func(*iam.Options)) (*iam.CreateUserOutput, error) {
+	if m.CreateUserFunc != nil {
+		return m.CreateUserFunc(ctx, input, opts...)
+	}
+	return &iam.CreateUserOutput{}, nil
+}
+
+func (m *MockIAMClient) PutUserPolicy(ctx context.Context, input *iam.PutUserPolicyInput, opts ...func(*iam.Options)) (*iam.PutUserPolicyOutput, error) {
+	if m.PutUserPolicyFunc != nil {
+		return m.PutUserPolicyFunc(ctx, input, opts...)
+	}
+	return &iam.PutUserPolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) ListAccessKeys(ctx context.Context, input *iam.ListAccessKeysInput, opts ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error) {
+	if m.ListAccessKeysFunc != nil {
+		return m.ListAccessKeysFunc(ctx, input, opts...)
+	}
+	return &iam.ListAccessKeysOutput{}, nil
+}
+
+func (m *MockIAMClient) CreateAccessKey(ctx context.Context, input *iam.CreateAccessKeyInput, opts ...func(*iam.Options)) (*iam.CreateAccessKeyOutput, error) {
+	if m.CreateAccessKeyFunc != nil {
+		return m.CreateAccessKeyFunc(ctx, input, opts...)
+	}
+	return &iam.CreateAccessKeyOutput{
+		AccessKey: &iamtypes.AccessKey{
+			AccessKeyId:     aws.String("test-access-key-id"),
+			SecretAccessKey: aws.String("test-secret-access-key"),
+			UserName:        input.UserName,
+		},
+	}, nil
+}
+
+func (m *MockIAMClient) DeleteAccessKey(ctx context.Context, input *iam.DeleteAccessKeyInput, opts ...func(*iam.Options)) (*iam.DeleteAccessKeyOutput, error) {
+	if m.DeleteAccessKeyFunc != nil {
+		return m.DeleteAccessKeyFunc(ctx, input, opts...)
+	}
+	return &iam.DeleteAccessKeyOutput{}, nil
+}
+
+func (m *MockIAMClient) ListUserPolicies(ctx context.Context, input *iam.ListUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListUserPoliciesOutput, error) {
+	if m.ListUserPoliciesFunc != nil {
+		return m.ListUserPoliciesFunc(ctx, input, opts...)
+	}
+	return &iam.ListUserPoliciesOutput{}, nil
+}
+
+func (m *MockIAMClient) DeleteUserPolicy(ctx context.Context, input *iam.DeleteUserPolicyInput, opts ...func(*iam.Options)) (*iam.DeleteUserPolicyOutput, error) {
+	if m.DeleteUserPolicyFunc != nil {
+		return m.DeleteUserPolicyFunc(ctx, input, opts...)
+	}
+	return &iam.DeleteUserPolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) ListAttachedUserPolicies(ctx context.Context, input *iam.ListAttachedUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListAttachedUserPoliciesOutput, error) {
+	if m.ListAttachedUserPoliciesFunc != nil {
+		return m.ListAttachedUserPoliciesFunc(ctx, input, opts...)
+	}
+	return &iam.ListAttachedUserPoliciesOutput{}, nil
+}
+
+func (m *MockIAMClient) DetachUserPolicy(ctx context.Context, input *iam.DetachUserPolicyInput, opts ...func(*iam.Options)) (*iam.DetachUserPolicyOutput, error) {
+	if m.DetachUserPolicyFunc != nil {
+		return m.DetachUserPolicyFunc(ctx, input, opts...)
+	}
+	return &iam.DetachUserPolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) DeleteUser(ctx context.Context, input *iam.DeleteUserInput, opts ...func(*iam.Options)) (*iam.DeleteUserOutput, error) {
+	if m.DeleteUserFunc != nil {
+		return m.DeleteUserFunc(ctx, input, opts...)
+	}
+	return &iam.DeleteUserOutput{}, nil
+}
+
+func (m *MockIAMClient) CreateRole(ctx context.Context, input *iam.CreateRoleInput, opts ...func(*iam.Options)) (*iam.CreateRoleOutput, error) {
+	if m.CreateRoleFunc != nil {
+		return m.CreateRoleFunc(ctx, input, opts...)
+	}
+	return &iam.CreateRoleOutput{
+		Role: &iamtypes.Role{
+			RoleName: input.RoleName,
+			Arn:      aws.String("arn:aws:iam::123456789012:role/" + aws.ToString(input.RoleName)),
+		},
+	}, nil
+}
+
+func (m *MockIAMClient) GetRole(ctx context.Context, input *iam.GetRoleInput, opts ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+	if m.GetRoleFunc != nil {
+		return m.GetRoleFunc(ctx, input, opts...)
+	}
+	return &iam.GetRoleOutput{
+		Role: &iamtypes.Role{
+			RoleName: input.RoleName,
+			Arn:      aws.String("arn:aws:iam::123456789012:role/" + aws.ToString(input.RoleName)),
+		},
+	}, nil
+}
+
+func (m *MockIAMClient) UpdateAssumeRolePolicy(ctx context.Context, input *iam.UpdateAssumeRolePolicyInput, opts ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error) {
+	if m.UpdateAssumeRolePolicyFunc != nil {
+		return m.UpdateAssumeRolePolicyFunc(ctx, input, opts...)
+	}
+	return &iam.UpdateAssumeRolePolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) PutRolePolicy(ctx context.Context, input *iam.PutRolePolicyInput, opts ...func(*iam.Options)) (*iam.PutRolePolicyOutput, error) {
+	if m.PutRolePolicyFunc != nil {
+		return m.PutRolePolicyFunc(ctx, input, opts...)
+	}
+	return &iam.PutRolePolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) ListRolePolicies(ctx context.Context, input *iam.ListRolePoliciesInput, opts ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error) {
+	if m.ListRolePoliciesFunc != nil {
+		return m.ListRolePoliciesFunc(ctx, input, opts...)
+	}
+	return &iam.ListRolePoliciesOutput{}, nil
+}
+
+func (m *MockIAMClient) DeleteRolePolicy(ctx context.Context, input *iam.DeleteRolePolicyInput, opts ...func(*iam.Options)) (*iam.DeleteRolePolicyOutput, error) {
+	if m.DeleteRolePolicyFunc != nil {
+		return m.DeleteRolePolicyFunc(ctx, input, opts...)
+	}
+	return &iam.DeleteRolePolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) ListAttachedRolePolicies(ctx context.Context, input *iam.ListAttachedRolePoliciesInput, opts ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error) {
+	if m.ListAttachedRolePoliciesFunc != nil {
+		return m.ListAttachedRolePoliciesFunc(ctx, input, opts...)
+	}
+	return &iam.ListAttachedRolePoliciesOutput{}, nil
+}
+
+func (m *MockIAMClient) DetachRolePolicy(ctx context.Context, input *iam.DetachRolePolicyInput, opts ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error) {
+	if m.DetachRolePolicyFunc != nil {
+		return m.DetachRolePolicyFunc(ctx, input, opts...)
+	}
+	return &iam.DetachRolePolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) DeleteRole(ctx context.Context, input *iam.DeleteRoleInput, opts ...func(*iam.Options)) (*iam.DeleteRoleOutput, error) {
+	if m.DeleteRoleFunc != nil {
+		return m.DeleteRoleFunc(ctx, input, opts...)
+	}
+	return &iam.DeleteRoleOutput{}, nil
+}
+
+func generateTags(count int) string {
+	tags := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		tags = append(tags, fmt.Sprintf("tag-%d=value", i))
+	}
+	return strings.Join(tags, ",")
+}
+
 var _ = Describe("ProvisionerServer DriverCreateBucket", func() {
 	var (
-		mockS3                   *MockS3Client
-		provisioner              *driver.ProvisionerServer
-		ctx                      context.Context
-		clientset                *fake.Clientset
-		bucketName               string
-		s3Params                 s3client.S3Params
-		request                  *cosiapi.DriverCreateBucketRequest
-		originalInitializeClient func(ctx context.Context, clientset kubernetes.Interface, parameters map[string]string) (*s3client.S3Client, *s3client.S3Params, error)
+		mockS3                   *MockS3Client
+		mockQuota                *MockQuotaClient
+		provisioner              *driver.ProvisionerServer
+		ctx                      context.Context
+		clientset                *fake.Clientset
+		bucketName               string
+		s3Params                 s3client.S3Params
+		request                  *cosiapi.DriverCreateBucketRequest
+		originalInitializeClient func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*s3client.S3Client, *s3client.S3Params, error)
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		mockS3 = &MockS3Client{}
+		mockS3.GetBucketLocationFunc = func(ctx context.Context, input *s3.GetBucketLocationInput, opts ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+			return &s3.GetBucketLocationOutput{LocationConstraint: types.BucketLocationConstraint(s3Params.Region)}, nil
+		}
+		mockQuota = &MockQuotaClient{}
+		clientset = fake.NewSimpleClientset()
+		bucketName = "test-bucket"
+		provisioner = &driver.ProvisionerServer{
+			Provisioner: "test-provisioner",
+			Clientset:   clientset,
+			BucketClientset: bucketfake.NewSimpleClientset(&bucketv1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+				Spec: bucketv1alpha1.BucketSpec{
+					BucketClaim: &corev1.ObjectReference{Namespace: "team-a", Name: "test-claim"},
+				},
+			}),
+		}
+		s3Params = s3client.S3Params{
+			AccessKey: "test-access-key",
+			SecretKey: "test-secret-key",
+			Endpoint:  "https://test-endpoint",
+			Region:    "us-west-2",
+		}
+		request = &cosiapi.DriverCreateBucketRequest{Name: bucketName}
+
+		// Storing the original method to restore it for other test suits
+		originalInitializeClient = driver.InitializeClient
+	})
+
+	AfterEach(func() {
+		// Restore the original InitializeClient function for other test suites
+		driver.InitializeClient = originalInitializeClient
+	})
+
+	JustBeforeEach(func() {
+		driver.InitializeClient = func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*s3client.S3Client, *s3client.S3Params, error) {
+			return &s3client.S3Client{S3Service: mockS3, QuotaService: mockQuota}, &s3Params, nil
+		}
+	})
+
+	It("should successfully create a new bucket", func() {
+		mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+			Expect(input.Bucket).To(Equal(&bucketName))
+			return &s3.CreateBucketOutput{}, nil
+		}
+
+		resp, err := provisioner.DriverCreateBucket(ctx, request)
+		Expect(err).To(BeNil())
+		Expect(resp).NotTo(BeNil())
+		Expect(resp.BucketId).To(Equal(bucketName))
+	})
+
+	It("should return AlreadyExists error if bucket already exists with different parameters", func() {
+		mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+			return nil, &types.BucketAlreadyExists{}
+		}
+
+		resp, err := provisioner.DriverCreateBucket(ctx, request)
+		Expect(resp).To(BeNil())
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+		Expect(err.Error()).To(ContainSubstring("Bucket already exists: test-bucket"))
+	})
+
+	It("should return success if bucket with same parameters already exists", func() {
+		mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+			return nil, &types.BucketAlreadyOwnedByYou{}
+		}
+
+		resp, err := provisioner.DriverCreateBucket(ctx, request)
+		Expect(err).To(BeNil())
+		Expect(resp).NotTo(BeNil())
+		Expect(resp.BucketId).To(Equal(bucketName))
+	})
+
+	It("should return Internal error for other S3 client errors", func() {
+		mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+			return nil, errors.New("SomeOtherError: Something went wrong")
+		}
+
+		resp, err := provisioner.DriverCreateBucket(ctx, request)
+		Expect(resp).To(BeNil())
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+		Expect(err.Error()).To(ContainSubstring("Failed to create bucket"))
+	})
+
+	Context("when the bucket is already owned by you", func() {
+		BeforeEach(func() {
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				return nil, &types.BucketAlreadyOwnedByYou{}
+			}
+		})
+
+		It("should return AlreadyExists error if the bucket is in another region", func() {
+			mockS3.GetBucketLocationFunc = func(ctx context.Context, input *s3.GetBucketLocationInput, opts ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+				Expect(*input.Bucket).To(Equal(bucketName))
+				return &s3.GetBucketLocationOutput{LocationConstraint: types.BucketLocationConstraintEuWest1}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+			Expect(err.Error()).To(ContainSubstring("Bucket already exists with different parameters: test-bucket: region is eu-west-1, requested us-west-2"))
+		})
+
+		It("should ignore an object lock the BucketClass does not request", func() {
+			mockS3.GetObjectLockConfigurationFunc = func(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+				Fail("GetObjectLockConfiguration should not be called")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+		})
+
+		It("should ignore a retention the BucketClass does not request", func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_OBJECT_LOCK_ENABLED": "true"}
+			mockS3.GetObjectLockConfigurationFunc = func(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+				return &s3.GetObjectLockConfigurationOutput{ObjectLockConfiguration: &types.ObjectLockConfiguration{
+					ObjectLockEnabled: types.ObjectLockEnabledEnabled,
+					Rule: &types.ObjectLockRule{DefaultRetention: &types.DefaultRetention{
+						Mode: types.ObjectLockRetentionModeGovernance,
+						Days: aws.Int32(30),
+					}},
+				}}, nil
+			}
+
+			mockS3.PutObjectLockConfigurationFunc = func(ctx context.Context, input *s3.PutObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.PutObjectLockConfigurationOutput, error) {
+				Fail("PutObjectLockConfiguration should not be called")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+		})
+
+		It("should ignore a default encryption of the object storage the BucketClass does not request", func() {
+			mockS3.GetBucketEncryptionFunc = func(ctx context.Context, input *s3.GetBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+				return &s3.GetBucketEncryptionOutput{ServerSideEncryptionConfiguration: &types.ServerSideEncryptionConfiguration{
+					Rules: []types.ServerSideEncryptionRule{{ApplyServerSideEncryptionByDefault: &types.ServerSideEncryptionByDefault{SSEAlgorithm: types.ServerSideEncryptionAes256}}},
+				}}, nil
+			}
+			mockS3.PutBucketEncryptionFunc = func(ctx context.Context, input *s3.PutBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.PutBucketEncryptionOutput, error) {
+				Fail("PutBucketEncryption should not be called")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+		})
+
+		It("should return Internal error if the object lock configuration cannot be read", func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_OBJECT_LOCK_ENABLED": "true"}
+			mockS3.GetObjectLockConfigurationFunc = func(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to get object lock configuration: test-bucket"))
+		})
+
+		It("should list every conflict and leave the bucket untouched", func() {
+			request.Parameters = map[string]string{
+				"COSI_BUCKET_VERSIONING": "Enabled",
+				"COSI_BUCKET_ENCRYPTION": "aws:kms",
+				"COSI_BUCKET_TAGS":       "team=payments,env=prod",
+			}
+			mockS3.GetBucketVersioningFunc = func(ctx context.Context, input *s3.GetBucketVersioningInput, opts ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+				return &s3.GetBucketVersioningOutput{Status: types.BucketVersioningStatusSuspended}, nil
+			}
+			mockS3.GetBucketEncryptionFunc = func(ctx context.Context, input *s3.GetBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+				return &s3.GetBucketEncryptionOutput{ServerSideEncryptionConfiguration: &types.ServerSideEncryptionConfiguration{
+					Rules: []types.ServerSideEncryptionRule{{ApplyServerSideEncryptionByDefault: &types.ServerSideEncryptionByDefault{SSEAlgorithm: types.ServerSideEncryptionAes256}}},
+				}}, nil
+			}
+			mockS3.GetBucketTaggingFunc = func(ctx context.Context, input *s3.GetBucketTaggingInput, opts ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+				return &s3.GetBucketTaggingOutput{TagSet: []types.Tag{
+					{Key: aws.String("team"), Value: aws.String("billing")},
+					{Key: aws.String("cosi.scality.com/provisioner"), Value: aws.String("other-provisioner")},
+				}}, nil
+			}
+			mockS3.PutBucketTaggingFunc = func(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+				Fail("PutBucketTagging should not be called")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+			Expect(err.Error()).To(ContainSubstring("versioning is Suspended, requested Enabled; " +
+				"encryption is AES256, requested aws:kms; " +
+				`tag cosi.scality.com/provisioner is "other-provisioner", requested "test-provisioner"; ` +
+				`tag team is "billing", requested "payments"`))
+		})
+
+		It("should add the missing tags to the tags of the bucket", func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_TAGS": "team=payments"}
+			mockS3.GetBucketTaggingFunc = func(ctx context.Context, input *s3.GetBucketTaggingInput, opts ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+				return &s3.GetBucketTaggingOutput{TagSet: []types.Tag{
+					{Key: aws.String("team"), Value: aws.String("payments")},
+					{Key: aws.String("cost-center"), Value: aws.String("42")},
+				}}, nil
+			}
+			var tags []types.Tag
+			mockS3.PutBucketTaggingFunc = func(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+				tags = input.Tagging.TagSet
+				return &s3.PutBucketTaggingOutput{}, nil
+			}
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(tags).To(ContainElements(
+				types.Tag{Key: aws.String("cost-center"), Value: aws.String("42")},
+				types.Tag{Key: aws.String("team"), Value: aws.String("payments")},
+				types.Tag{Key: aws.String("cosi.scality.com/bucket"), Value: aws.String(bucketName)},
+			))
+		})
+
+		It("should return Internal error when the bucket location cannot be read", func() {
+			mockS3.GetBucketLocationFunc = func(ctx context.Context, input *s3.GetBucketLocationInput, opts ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to get bucket location: test-bucket"))
+		})
+
+		It("should return Internal error when the bucket tags cannot be read", func() {
+			mockS3.GetBucketTaggingFunc = func(ctx context.Context, input *s3.GetBucketTaggingInput, opts ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to get bucket tags: test-bucket"))
+		})
+	})
+
+	Context("with a CORS ConfigMap", func() {
+		corsDocument := `
+CORSRules:
+- AllowedOrigins: ["https://app.example.com"]
+  AllowedMethods: ["GET", "PUT"]
+  AllowedHeaders: ["*"]
+  MaxAgeSeconds: 3000
+`
+		expectedRules := []types.CORSRule{{
+			AllowedOrigins: []string{"https://app.example.com"},
+			AllowedMethods: []string{"GET", "PUT"},
+			AllowedHeaders: []string{"*"},
+			MaxAgeSeconds:  aws.Int32(3000),
+		}}
+
+		createConfigMap := func(document string) {
+			_, err := clientset.CoreV1().ConfigMaps("cosi-driver").Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "cors", Namespace: "cosi-driver"},
+				Data:       map[string]string{"cors.json": document},
+			}, metav1.CreateOptions{})
+			Expect(err).To(BeNil())
+		}
+
+		BeforeEach(func() {
+			request.Parameters = map[string]string{
+				"COSI_BUCKET_CORS_CONFIGMAP_NAME":      "cors",
+				"COSI_BUCKET_CORS_CONFIGMAP_NAMESPACE": "cosi-driver",
+			}
+		})
+
+		It("should configure the CORS rules after creating the bucket", func() {
+			createConfigMap(corsDocument)
+			var rules []types.CORSRule
+			mockS3.PutBucketCorsFunc = func(ctx context.Context, input *s3.PutBucketCorsInput, opts ...func(*s3.Options)) (*s3.PutBucketCorsOutput, error) {
+				Expect(input.Bucket).To(Equal(&bucketName))
+				rules = input.CORSConfiguration.CORSRules
+				return &s3.PutBucketCorsOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(rules).To(Equal(expectedRules))
+		})
+
+		DescribeTable("should return InvalidArgument error for invalid CORS rules",
+			func(document string, message string) {
+				createConfigMap(document)
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("no rules", `{"CORSRules":[]}`, "between 1 and 100 rules are required"),
+			Entry("no origin", `{"CORSRules":[{"AllowedMethods":["GET"]}]}`, "rule 0 has no allowed origin"),
+			Entry("no method", `{"CORSRules":[{"AllowedOrigins":["*"]}]}`, "rule 0 has no allowed method"),
+			Entry("unsupported method", `{"CORSRules":[{"AllowedOrigins":["*"],"AllowedMethods":["PATCH"]}]}`, "unsupported method PATCH"),
+			Entry("negative max age", `{"CORSRules":[{"AllowedOrigins":["*"],"AllowedMethods":["GET"],"MaxAgeSeconds":-1}]}`, "negative MaxAgeSeconds"),
+			Entry("unknown field", `{"CORSRules":[{"AllowedOrigin":["*"],"AllowedMethods":["GET"]}]}`, "invalid CORS configuration"),
+		)
+
+		Context("when the bucket is already owned by you", func() {
+			BeforeEach(func() {
+				createConfigMap(corsDocument)
+				mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+					return nil, &types.BucketAlreadyOwnedByYou{}
+				}
+			})
+
+			It("should return success if the CORS rules match", func() {
+				mockS3.GetBucketCorsFunc = func(ctx context.Context, input *s3.GetBucketCorsInput, opts ...func(*s3.Options)) (*s3.GetBucketCorsOutput, error) {
+					return &s3.GetBucketCorsOutput{CORSRules: expectedRules}, nil
+				}
+				mockS3.PutBucketCorsFunc = func(ctx context.Context, input *s3.PutBucketCorsInput, opts ...func(*s3.Options)) (*s3.PutBucketCorsOutput, error) {
+					Fail("PutBucketCors should not be called")
+					return nil, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(err).To(BeNil())
+				Expect(resp.BucketId).To(Equal(bucketName))
+			})
+
+			It("should configure the CORS rules if the bucket has none", func() {
+				configured := false
+				mockS3.GetBucketCorsFunc = func(ctx context.Context, input *s3.GetBucketCorsInput, opts ...func(*s3.Options)) (*s3.GetBucketCorsOutput, error) {
+					return nil, &smithy.GenericAPIError{Code: "NoSuchCORSConfiguration"}
+				}
+				mockS3.PutBucketCorsFunc = func(ctx context.Context, input *s3.PutBucketCorsInput, opts ...func(*s3.Options)) (*s3.PutBucketCorsOutput, error) {
+					configured = true
+					return &s3.PutBucketCorsOutput{}, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(err).To(BeNil())
+				Expect(resp.BucketId).To(Equal(bucketName))
+				Expect(configured).To(BeTrue())
+			})
+
+			It("should return AlreadyExists error if the CORS rules drifted", func() {
+				mockS3.GetBucketCorsFunc = func(ctx context.Context, input *s3.GetBucketCorsInput, opts ...func(*s3.Options)) (*s3.GetBucketCorsOutput, error) {
+					return &s3.GetBucketCorsOutput{CORSRules: []types.CORSRule{{
+						AllowedOrigins: []string{"*"},
+						AllowedMethods: []string{"GET"},
+					}}}, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+				Expect(err.Error()).To(ContainSubstring("Bucket already exists with different parameters: test-bucket: CORS rules differ from the requested ones"))
+			})
+		})
+	})
+
+	Context("with a bucket policy", func() {
+		denyInsecureTransport := `{
+  "Version": "2012-10-17",
+  "Statement": [{
+    "Sid": "DenyInsecureTransport",
+    "Effect": "Deny",
+    "Principal": "*",
+    "Action": "s3:*",
+    "Resource": ["arn:aws:s3:::{{ .BucketName }}", "arn:aws:s3:::{{ .BucketName }}/*"],
+    "Condition": {"Bool": {"aws:SecureTransport": "false"}}
+  }]
+}`
+		expectedPolicy := `{"Version":"2012-10-17","Statement":[{"Sid":"DenyInsecureTransport","Effect":"Deny","Principal":"*","Action":"s3:*",` +
+			`"Resource":["arn:aws:s3:::test-bucket","arn:aws:s3:::test-bucket/*"],"Condition":{"Bool":{"aws:SecureTransport":"false"}}}]}`
+
+		BeforeEach(func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_POLICY": denyInsecureTransport}
+		})
+
+		It("should attach the rendered policy after creating the bucket", func() {
+			var policy string
+			mockS3.PutBucketPolicyFunc = func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+				Expect(input.Bucket).To(Equal(&bucketName))
+				policy = aws.ToString(input.Policy)
+				return &s3.PutBucketPolicyOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(policy).To(Equal(expectedPolicy))
+		})
+
+		It("should attach the policy of a ConfigMap", func() {
+			request.Parameters = map[string]string{
+				"COSI_BUCKET_POLICY_CONFIGMAP_NAME":      "bucket-policy",
+				"COSI_BUCKET_POLICY_CONFIGMAP_NAMESPACE": "cosi-driver",
+			}
+			_, err := clientset.CoreV1().ConfigMaps("cosi-driver").Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "bucket-policy", Namespace: "cosi-driver"},
+				Data:       map[string]string{"bucket-policy.json": denyInsecureTransport},
+			}, metav1.CreateOptions{})
+			Expect(err).To(BeNil())
+			var policy string
+			mockS3.PutBucketPolicyFunc = func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+				policy = aws.ToString(input.Policy)
+				return &s3.PutBucketPolicyOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(policy).To(Equal(expectedPolicy))
+		})
+
+		It("should attach the policy again when the bucket is already owned by you", func() {
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				return nil, &types.BucketAlreadyOwnedByYou{}
+			}
+			attached := false
+			mockS3.PutBucketPolicyFunc = func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+				attached = true
+				return &s3.PutBucketPolicyOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(attached).To(BeTrue())
+		})
+
+		It("should return InvalidArgument error when the policy is rejected as malformed", func() {
+			mockS3.PutBucketPolicyFunc = func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "MalformedPolicy", Message: "Policy has invalid action"}
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring("bucket policy rejected: Policy has invalid action"))
+		})
+
+		It("should return Internal error for other errors", func() {
+			mockS3.PutBucketPolicyFunc = func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to attach bucket policy: test-bucket"))
+		})
+
+		DescribeTable("should return InvalidArgument error for invalid policies without creating the bucket",
+			func(parameters map[string]string, message string) {
+				request.Parameters = parameters
+				mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+					Fail("CreateBucket should not be called")
+					return nil, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("inline policy with a ConfigMap",
+				map[string]string{"COSI_BUCKET_POLICY": "{}", "COSI_BUCKET_POLICY_CONFIGMAP_NAME": "bucket-policy"},
+				"mutually exclusive"),
+			Entry("unknown template field",
+				map[string]string{"COSI_BUCKET_POLICY": `{"Version":"2012-10-17","Statement":[{"Resource":"{{ .Namespace }}"}]}`},
+				"failed to render bucket policy template"),
+			Entry("missing principal",
+				map[string]string{"COSI_BUCKET_POLICY": `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::{{ .BucketName }}/*"}]}`},
+				"bucket policy statement 0 has no Principal"),
+			Entry("resource of another bucket",
+				map[string]string{"COSI_BUCKET_POLICY": `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::other/*"}]}`},
+				"references a resource outside of bucket test-bucket"),
+		)
+	})
+
+	Context("with COSI_EXISTING_BUCKET_NAME", func() {
+		BeforeEach(func() {
+			request.Parameters = map[string]string{"COSI_EXISTING_BUCKET_NAME": "legacy-bucket"}
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				Fail("CreateBucket should not be called")
+				return nil, nil
+			}
+			mockS3.ListBucketsFunc = func(ctx context.Context, input *s3.ListBucketsInput, opts ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+				return &s3.ListBucketsOutput{Buckets: []types.Bucket{{Name: aws.String("legacy-bucket-2")}, {Name: aws.String("legacy-bucket")}}}, nil
+			}
+		})
+
+		It("should adopt the existing bucket and return its name as the bucket ID", func() {
+			mockS3.HeadBucketFunc = func(ctx context.Context, input *s3.HeadBucketInput, opts ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+				Expect(*input.Bucket).To(Equal("legacy-bucket"))
+				return &s3.HeadBucketOutput{}, nil
+			}
+			mockS3.PutBucketTaggingFunc = func(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+				Fail("PutBucketTagging should not replace the tags of an adopted bucket")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal("legacy-bucket"))
+		})
+
+		It("should return AlreadyExists error when another Bucket already adopted the existing bucket", func(specCtx SpecContext) {
+			informer, err := driver.WatchBucketObjects(specCtx, bucketfake.NewSimpleClientset(&bucketv1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-bucket"},
+				Spec:       bucketv1alpha1.BucketSpec{Parameters: map[string]string{"COSI_EXISTING_BUCKET_NAME": "legacy-bucket"}},
+				Status:     bucketv1alpha1.BucketStatus{BucketID: "legacy-bucket"},
+			}))
+			Expect(err).To(BeNil())
+			provisioner.BucketInformer = informer
+			mockS3.HeadBucketFunc = func(ctx context.Context, input *s3.HeadBucketInput, opts ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+				Fail("HeadBucket should not be called for a bucket adopted by another Bucket")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+			Expect(err.Error()).To(ContainSubstring("existing bucket legacy-bucket is already adopted by Bucket other-bucket"))
+		})
+
+		It("should adopt the existing bucket again on retries of the same Bucket", func(specCtx SpecContext) {
+			informer, err := driver.WatchBucketObjects(specCtx, bucketfake.NewSimpleClientset(&bucketv1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+				Spec:       bucketv1alpha1.BucketSpec{Parameters: map[string]string{"COSI_EXISTING_BUCKET_NAME": "legacy-bucket"}},
+				Status:     bucketv1alpha1.BucketStatus{BucketID: "legacy-bucket"},
+			}))
+			Expect(err).To(BeNil())
+			provisioner.BucketInformer = informer
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal("legacy-bucket"))
+		})
+
+		It("should apply the requested quota to the existing bucket", func() {
+			request.Parameters["COSI_BUCKET_QUOTA"] = "1Gi"
+			var quotaBucket string
+			mockQuota.PutBucketQuotaFunc = func(ctx context.Context, name string, value int64) error {
+				quotaBucket = name
+				return nil
+			}
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(quotaBucket).To(Equal("legacy-bucket"))
+		})
+
+		It("should leave the ACL and public access block of the existing bucket alone", func() {
+			provisioner.BlockPublicAccess = true
+			mockS3.PutBucketAclFunc = func(ctx context.Context, input *s3.PutBucketAclInput, opts ...func(*s3.Options)) (*s3.PutBucketAclOutput, error) {
+				Fail("PutBucketAcl should not be called on an adopted bucket")
+				return nil, nil
+			}
+			mockS3.PutPublicAccessBlockFunc = func(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error) {
+				Fail("PutPublicAccessBlock should not be called on an adopted bucket")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal("legacy-bucket"))
+		})
+
+		Context("with a bucket policy", func() {
+			policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Principal":"*","Action":"s3:DeleteBucket","Resource":"arn:aws:s3:::{{ .BucketName }}"}]}`
+
+			BeforeEach(func() {
+				request.Parameters["COSI_BUCKET_POLICY"] = policy
+			})
+
+			It("should attach the policy when the existing bucket has none", func() {
+				var attached string
+				mockS3.PutBucketPolicyFunc = func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+					Expect(*input.Bucket).To(Equal("legacy-bucket"))
+					attached = aws.ToString(input.Policy)
+					return &s3.PutBucketPolicyOutput{}, nil
+				}
+
+				_, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(err).To(BeNil())
+				Expect(attached).To(ContainSubstring("arn:aws:s3:::legacy-bucket"))
+			})
+
+			It("should keep an identical policy of the existing bucket", func() {
+				mockS3.GetBucketPolicyFunc = func(ctx context.Context, input *s3.GetBucketPolicyInput, opts ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error) {
+					return &s3.GetBucketPolicyOutput{Policy: aws.String(`{"Version": "2012-10-17", "Statement": [{"Effect": "Deny", "Principal": "*", ` +
+						`"Action": "s3:DeleteBucket", "Resource": "arn:aws:s3:::legacy-bucket"}]}`)}, nil
+				}
+				mockS3.PutBucketPolicyFunc = func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+					Fail("PutBucketPolicy should not be called on an adopted bucket with the same policy")
+					return nil, nil
+				}
+
+				_, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(err).To(BeNil())
+			})
+
+			It("should return AlreadyExists error when the existing bucket has another policy", func() {
+				mockS3.GetBucketPolicyFunc = func(ctx context.Context, input *s3.GetBucketPolicyInput, opts ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error) {
+					return &s3.GetBucketPolicyOutput{Policy: aws.String(`{"Version":"2012-10-17","Statement":[]}`)}, nil
+				}
+				mockS3.PutBucketPolicyFunc = func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+					Fail("PutBucketPolicy should not replace the policy of an adopted bucket")
+					return nil, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+				Expect(err.Error()).To(ContainSubstring("bucket policy differs from the requested one"))
+			})
+		})
+
+		It("should return AlreadyExists error when the existing bucket has other lifecycle rules", func() {
+			request.Parameters["COSI_BUCKET_LIFECYCLE_EXPIRATION_DAYS"] = "30"
+			mockS3.GetBucketLifecycleConfigurationFunc = func(ctx context.Context, input *s3.GetBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+				return &s3.GetBucketLifecycleConfigurationOutput{Rules: []types.LifecycleRule{{
+					ID:         aws.String("expire-logs"),
+					Status:     types.ExpirationStatusEnabled,
+					Filter:     &types.LifecycleRuleFilter{Prefix: aws.String("logs/")},
+					Expiration: &types.LifecycleExpiration{Days: aws.Int32(7)},
+				}}}, nil
+			}
+			mockS3.PutBucketLifecycleConfigurationFunc = func(ctx context.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+				Fail("PutBucketLifecycleConfiguration should not replace the rules of an adopted bucket")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+			Expect(err.Error()).To(ContainSubstring("lifecycle rules differ from the requested ones"))
+		})
+
+		It("should keep lifecycle rules read back with the defaults of the object storage", func() {
+			request.Parameters["COSI_BUCKET_LIFECYCLE_EXPIRATION_DAYS"] = "30"
+			mockS3.GetBucketLifecycleConfigurationFunc = func(ctx context.Context, input *s3.GetBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+				return &s3.GetBucketLifecycleConfigurationOutput{Rules: []types.LifecycleRule{{
+					ID:         aws.String("cosi-bucket-lifecycle"),
+					Status:     types.ExpirationStatusEnabled,
+					Filter:     &types.LifecycleRuleFilter{},
+					Expiration: &types.LifecycleExpiration{Days: aws.Int32(30)},
+				}}}, nil
+			}
+			mockS3.PutBucketLifecycleConfigurationFunc = func(ctx context.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+				Fail("PutBucketLifecycleConfiguration should not be called on an adopted bucket with the same rules")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal("legacy-bucket"))
+		})
+
+		It("should keep bucket notifications read back in another order", func() {
+			request.Parameters["COSI_BUCKET_NOTIFICATION_DESTINATION"] = "kafka-events"
+			request.Parameters["COSI_BUCKET_NOTIFICATION_PREFIX"] = "logs/"
+			mockS3.GetBucketNotificationConfigurationFunc = func(ctx context.Context, input *s3.GetBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketNotificationConfigurationOutput, error) {
+				return &s3.GetBucketNotificationConfigurationOutput{
+					QueueConfigurations: []types.QueueConfiguration{{
+						Id:       aws.String("cosi-bucket-notification"),
+						QueueArn: aws.String("arn:scality:bucketnotif:::kafka-events"),
+						Events:   []types.Event{"s3:ObjectRemoved:*", "s3:ObjectCreated:*"},
+						Filter: &types.NotificationConfigurationFilter{Key: &types.S3KeyFilter{
+							FilterRules: []types.FilterRule{{Name: "Prefix", Value: aws.String("logs/")}},
+						}},
+					}},
+					TopicConfigurations: []types.TopicConfiguration{},
+				}, nil
+			}
+			mockS3.PutBucketNotificationConfigurationFunc = func(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error) {
+				Fail("PutBucketNotificationConfiguration should not be called on an adopted bucket with the same notifications")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal("legacy-bucket"))
+		})
+
+		It("should return AlreadyExists error when the existing bucket notifies other events", func() {
+			request.Parameters["COSI_BUCKET_NOTIFICATION_DESTINATION"] = "kafka-events"
+			mockS3.GetBucketNotificationConfigurationFunc = func(ctx context.Context, input *s3.GetBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketNotificationConfigurationOutput, error) {
+				return &s3.GetBucketNotificationConfigurationOutput{QueueConfigurations: []types.QueueConfiguration{{
+					Id:       aws.String("cosi-bucket-notification"),
+					QueueArn: aws.String("arn:scality:bucketnotif:::kafka-events"),
+					Events:   []types.Event{"s3:ObjectCreated:*"},
+				}}}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+			Expect(err.Error()).To(ContainSubstring("bucket notifications differ from the requested ones"))
+		})
+
+		Context("with a bucket replication", func() {
+			BeforeEach(func() {
+				request.Parameters["COSI_BUCKET_REPLICATION_DESTINATION_BUCKET"] = "{{.BucketName}}-replica"
+				request.Parameters["COSI_BUCKET_REPLICATION_ROLE"] = "arn:aws:iam::123456789012:role/replication"
+				request.Parameters["COSI_BUCKET_REPLICATION_PREFIXES"] = "logs/,data/"
+				mockS3.PutBucketReplicationFunc = func(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error) {
+					Fail("PutBucketReplication should not replace the replication of an adopted bucket")
+					return nil, nil
+				}
+			})
+
+			It("should keep a replication read back with the defaults of the object storage", func() {
+				mockS3.GetBucketReplicationFunc = func(ctx context.Context, input *s3.GetBucketReplicationInput, opts ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error) {
+					rule := func(id, prefix string) types.ReplicationRule {
+						return types.ReplicationRule{
+							ID:                      aws.String(id),
+							Filter:                  &types.ReplicationRuleFilter{Prefix: aws.String(prefix)},
+							Priority:                aws.Int32(0),
+							Status:                  types.ReplicationRuleStatusEnabled,
+							DeleteMarkerReplication: &types.DeleteMarkerReplication{Status: types.DeleteMarkerReplicationStatusDisabled},
+							Destination: &types.Destination{
+								Bucket:       aws.String("arn:aws:s3:::legacy-bucket-replica"),
+								StorageClass: types.StorageClassStandard,
+							},
+						}
+					}
+					return &s3.GetBucketReplicationOutput{ReplicationConfiguration: &types.ReplicationConfiguration{
+						Role:  aws.String("arn:aws:iam::123456789012:role/replication"),
+						Rules: []types.ReplicationRule{rule("cosi-replication-1", "data/"), rule("cosi-replication-0", "logs/")},
+					}}, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(err).To(BeNil())
+				Expect(resp.BucketId).To(Equal("legacy-bucket"))
+			})
+
+			It("should return AlreadyExists error when the existing bucket replicates elsewhere", func() {
+				mockS3.GetBucketReplicationFunc = func(ctx context.Context, input *s3.GetBucketReplicationInput, opts ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error) {
+					return &s3.GetBucketReplicationOutput{ReplicationConfiguration: &types.ReplicationConfiguration{
+						Role: aws.String("arn:aws:iam::123456789012:role/replication"),
+						Rules: []types.ReplicationRule{{
+							ID:          aws.String("cosi-replication-0"),
+							Prefix:      aws.String(""),
+							Status:      types.ReplicationRuleStatusEnabled,
+							Destination: &types.Destination{Bucket: aws.String("arn:aws:s3:::other-replica")},
+						}},
+					}}, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+				Expect(err.Error()).To(ContainSubstring("replication differs from the requested one"))
+			})
+		})
+
+		It("should return NotFound error when the bucket does not exist", func() {
+			mockS3.HeadBucketFunc = func(ctx context.Context, input *s3.HeadBucketInput, opts ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+				return nil, &types.NotFound{}
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+			Expect(err.Error()).To(ContainSubstring("existing bucket not found: legacy-bucket"))
+		})
+
+		It("should return PermissionDenied error when the bucket is not accessible", func() {
+			mockS3.HeadBucketFunc = func(ctx context.Context, input *s3.HeadBucketInput, opts ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "Forbidden"}
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+			Expect(err.Error()).To(ContainSubstring("not accessible with the configured credentials"))
+		})
+
+		It("should return PermissionDenied error when the bucket belongs to another account", func() {
+			mockS3.ListBucketsFunc = func(ctx context.Context, input *s3.ListBucketsInput, opts ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+				Expect(*input.Prefix).To(Equal("legacy-bucket"))
+				return &s3.ListBucketsOutput{Buckets: []types.Bucket{{Name: aws.String("legacy-bucket-2")}}}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+			Expect(err.Error()).To(ContainSubstring("existing bucket is not owned by the configured credentials: legacy-bucket"))
+		})
+
+		It("should return Internal error when the bucket cannot be checked", func() {
+			mockS3.HeadBucketFunc = func(ctx context.Context, input *s3.HeadBucketInput, opts ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to check existing bucket: legacy-bucket"))
+		})
+
+		DescribeTable("should return InvalidArgument error for invalid adoption parameters",
+			func(parameters map[string]string, message string) {
+				request.Parameters = parameters
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("invalid name",
+				map[string]string{"COSI_EXISTING_BUCKET_NAME": "Legacy_Bucket"},
+				"invalid bucket name"),
+			Entry("name template",
+				map[string]string{"COSI_EXISTING_BUCKET_NAME": "legacy-bucket", "COSI_BUCKET_NAME_TEMPLATE": "{{.ClaimName}}"},
+				"mutually exclusive"),
+		)
+	})
+
+	Context("with a bucket name template", func() {
+		var hash string
+
+		BeforeEach(func() {
+			sum := sha256.Sum256([]byte(bucketName))
+			hash = hex.EncodeToString(sum[:])
+			request.Parameters = map[string]string{"COSI_BUCKET_NAME_TEMPLATE": "{{.Namespace}}-{{.ClaimName}}-{{.Hash 6}}"}
+		})
+
+		It("should create the bucket with the rendered name and return it as the bucket ID", func() {
+			expectedName := "team-a-test-claim-" + hash[:6]
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				Expect(*input.Bucket).To(Equal(expectedName))
+				return &s3.CreateBucketOutput{}, nil
+			}
+			var tags []types.Tag
+			mockS3.PutBucketTaggingFunc = func(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+				Expect(*input.Bucket).To(Equal(expectedName))
+				tags = input.Tagging.TagSet
+				return &s3.PutBucketTaggingOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(expectedName))
+			Expect(tags).To(ContainElement(types.Tag{Key: aws.String("cosi.scality.com/bucket"), Value: aws.String(bucketName)}))
+		})
+
+		It("should render the bucket policy with the rendered name", func() {
+			request.Parameters["COSI_BUCKET_POLICY"] = `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Principal":"*","Action":"s3:*","Resource":"arn:aws:s3:::{{ .BucketName }}/*"}]}`
+			var policy string
+			mockS3.PutBucketPolicyFunc = func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+				policy = *input.Policy
+				return &s3.PutBucketPolicyOutput{}, nil
+			}
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(policy).To(ContainSubstring("arn:aws:s3:::team-a-test-claim-" + hash[:6] + "/*"))
+		})
+
+		It("should prefix and lowercase the bucket name", func() {
+			request.Parameters = map[string]string{
+				"COSI_BUCKET_NAME_PREFIX":   "Prod-",
+				"COSI_BUCKET_NAME_TEMPLATE": "{{.ClaimName}}.{{.Hash 8}}",
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal("prod-test-claim." + hash[:8]))
+		})
+
+		It("should not fetch the bucket object when the template does not use the bucket claim", func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_NAME_TEMPLATE": "data-{{.Hash 8}}"}
+			bucketClientset := bucketfake.NewSimpleClientset()
+			provisioner.BucketClientset = bucketClientset
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal("data-" + hash[:8]))
+			Expect(bucketClientset.Actions()).To(BeEmpty())
+		})
+
+		It("should fetch the bucket object once when the template uses the bucket claim twice", func() {
+			bucketClientset := bucketfake.NewSimpleClientset(&bucketv1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: bucketName},
+				Spec: bucketv1alpha1.BucketSpec{
+					BucketClaim: &corev1.ObjectReference{Namespace: "team-a", Name: "test-claim"},
+				},
+			})
+			provisioner.BucketClientset = bucketClientset
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(bucketClientset.Actions()).To(HaveLen(1))
+		})
+
+		It("should return NotFound error when the bucket object does not exist", func() {
+			provisioner.BucketClientset = bucketfake.NewSimpleClientset()
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+			Expect(err.Error()).To(ContainSubstring("bucket object test-bucket not found"))
+		})
+
+		It("should prefix the COSI bucket name without a template", func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_NAME_PREFIX": "prod-"}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal("prod-test-bucket"))
+		})
+
+		It("should return the same name when the bucket is already owned by you", func() {
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				return nil, &types.BucketAlreadyOwnedByYou{}
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal("team-a-test-claim-" + hash[:6]))
+		})
+
+		DescribeTable("should return InvalidArgument error for names breaking the S3 rules",
+			func(parameters map[string]string, message string) {
+				request.Parameters = parameters
+				mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+					Fail("CreateBucket should not be called")
+					return nil, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("too long",
+				map[string]string{"COSI_BUCKET_NAME_TEMPLATE": "{{.Namespace}}-{{.ClaimName}}-{{.Hash 64}}"},
+				"must be between 3 and 63 characters long"),
+			Entry("invalid characters",
+				map[string]string{"COSI_BUCKET_NAME_TEMPLATE": "{{.Namespace}}_{{.ClaimName}}"},
+				"must contain only lowercase letters, numbers, dots and hyphens"),
+			Entry("trailing hyphen",
+				map[string]string{"COSI_BUCKET_NAME_TEMPLATE": "{{.ClaimName}}-"},
+				"start and end with a letter or number"),
+			Entry("adjacent dots",
+				map[string]string{"COSI_BUCKET_NAME_TEMPLATE": "{{.Namespace}}..{{.ClaimName}}"},
+				"must not contain two adjacent dots"),
+			Entry("IP address",
+				map[string]string{"COSI_BUCKET_NAME_TEMPLATE": "192.168.1.1"},
+				"must not be formatted as an IP address"),
+			Entry("reserved prefix",
+				map[string]string{"COSI_BUCKET_NAME_PREFIX": "xn--"},
+				"the xn-- prefix and -s3alias suffix are reserved"),
+			Entry("unknown field",
+				map[string]string{"COSI_BUCKET_NAME_TEMPLATE": "{{.Team}}"},
+				"failed to render COSI_BUCKET_NAME_TEMPLATE"),
+			Entry("invalid hash length",
+				map[string]string{"COSI_BUCKET_NAME_TEMPLATE": "{{.ClaimName}}-{{.Hash 0}}"},
+				"hash length must be between 1 and 64"),
+			Entry("malformed template",
+				map[string]string{"COSI_BUCKET_NAME_TEMPLATE": "{{.ClaimName"},
+				"invalid COSI_BUCKET_NAME_TEMPLATE"),
+		)
+	})
+
+	Context("with public access blocked", func() {
+		BeforeEach(func() {
+			provisioner.BlockPublicAccess = true
+		})
+
+		It("should create a private bucket and block public access before configuring it", func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_POLICY": `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Principal":"*","Action":"s3:*","Resource":"arn:aws:s3:::{{ .BucketName }}/*"}]}`}
+			var calls []string
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				Expect(input.ACL).To(Equal(types.BucketCannedACLPrivate))
+				return &s3.CreateBucketOutput{}, nil
+			}
+			mockS3.PutBucketAclFunc = func(ctx context.Context, input *s3.PutBucketAclInput, opts ...func(*s3.Options)) (*s3.PutBucketAclOutput, error) {
+				Expect(input.ACL).To(Equal(types.BucketCannedACLPrivate))
+				calls = append(calls, "acl")
+				return &s3.PutBucketAclOutput{}, nil
+			}
+			mockS3.PutPublicAccessBlockFunc = func(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error) {
+				Expect(input.PublicAccessBlockConfiguration).To(Equal(&types.PublicAccessBlockConfiguration{
+					BlockPublicAcls:       aws.Bool(true),
+					IgnorePublicAcls:      aws.Bool(true),
+					BlockPublicPolicy:     aws.Bool(true),
+					RestrictPublicBuckets: aws.Bool(true),
+				}))
+				calls = append(calls, "public-access-block")
+				return &s3.PutPublicAccessBlockOutput{}, nil
+			}
+			mockS3.PutBucketPolicyFunc = func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+				calls = append(calls, "policy")
+				return &s3.PutBucketPolicyOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(calls).To(Equal([]string{"acl", "public-access-block", "policy"}))
+		})
+
+		It("should block public access on the retry of a creation interrupted before the bucket was made private", func() {
+			created := false
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				if created {
+					return nil, &types.BucketAlreadyOwnedByYou{}
+				}
+				created = true
+				return &s3.CreateBucketOutput{}, nil
+			}
+			aclFailures := 1
+			mockS3.PutBucketAclFunc = func(ctx context.Context, input *s3.PutBucketAclInput, opts ...func(*s3.Options)) (*s3.PutBucketAclOutput, error) {
+				if aclFailures > 0 {
+					aclFailures--
+					return nil, errors.New("SomeOtherError: Something went wrong")
+				}
+				return &s3.PutBucketAclOutput{}, nil
+			}
+			blocked := false
+			mockS3.PutPublicAccessBlockFunc = func(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error) {
+				blocked = true
+				return &s3.PutPublicAccessBlockOutput{}, nil
+			}
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(blocked).To(BeFalse())
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(aclFailures).To(Equal(0))
+			Expect(blocked).To(BeTrue())
+		})
+
+		It("should block public access when the bucket is already owned by you and the BucketClass requests it", func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_BLOCK_PUBLIC_ACCESS": "true"}
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				return nil, &types.BucketAlreadyOwnedByYou{}
+			}
+			blocked := false
+			mockS3.PutPublicAccessBlockFunc = func(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error) {
+				blocked = true
+				return &s3.PutPublicAccessBlockOutput{}, nil
+			}
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(blocked).To(BeTrue())
+		})
+
+		It("should keep the private ACL when the object storage does not support public access blocks", func() {
+			mockS3.PutPublicAccessBlockFunc = func(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "NotImplemented"}
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+		})
+
+		It("should return Internal error when public access cannot be blocked", func() {
+			mockS3.PutPublicAccessBlockFunc = func(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to block bucket public access: test-bucket"))
+		})
+
+		It("should leave the bucket ACL untouched when the BucketClass opts out", func() {
+			request.Parameters = map[string]string{
+				"COSI_BUCKET_BLOCK_PUBLIC_ACCESS": "false",
+				"COSI_BUCKET_POLICY":              `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::{{ .BucketName }}/*"}]}`,
+			}
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				Expect(input.ACL).To(BeEmpty())
+				return &s3.CreateBucketOutput{}, nil
+			}
+			mockS3.PutBucketAclFunc = func(ctx context.Context, input *s3.PutBucketAclInput, opts ...func(*s3.Options)) (*s3.PutBucketAclOutput, error) {
+				Fail("PutBucketAcl should not be called")
+				return nil, nil
+			}
+			mockS3.PutPublicAccessBlockFunc = func(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error) {
+				Fail("PutPublicAccessBlock should not be called")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+		})
+
+		DescribeTable("should return InvalidArgument error for parameters making the bucket public",
+			func(parameters map[string]string, message string) {
+				request.Parameters = parameters
+				mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+					Fail("CreateBucket should not be called")
+					return nil, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("anonymous principal",
+				map[string]string{"COSI_BUCKET_POLICY": `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::{{ .BucketName }}/*"}]}`},
+				"bucket policy statement 0 grants public access"),
+			Entry("anonymous AWS principal",
+				map[string]string{"COSI_BUCKET_POLICY": `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123:root","*"]},"Action":"s3:GetObject","Resource":"arn:aws:s3:::{{ .BucketName }}/*"}]}`},
+				"bucket policy statement 0 grants public access"),
+			Entry("invalid opt-out value",
+				map[string]string{"COSI_BUCKET_BLOCK_PUBLIC_ACCESS": "never"},
+				"invalid COSI_BUCKET_BLOCK_PUBLIC_ACCESS value: never"),
+		)
+	})
+
+	Context("with public access blocked by the BucketClass", func() {
+		It("should block public access when the driver default allows it", func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_BLOCK_PUBLIC_ACCESS": "true"}
+			blocked := false
+			mockS3.PutPublicAccessBlockFunc = func(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error) {
+				blocked = true
+				return &s3.PutPublicAccessBlockOutput{}, nil
+			}
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(blocked).To(BeTrue())
+		})
+	})
+
+	Context("with bucket notifications", func() {
+		BeforeEach(func() {
+			request.Parameters = map[string]string{
+				"COSI_BUCKET_NOTIFICATION_DESTINATION": "kafka-events",
+				"COSI_BUCKET_NOTIFICATION_EVENTS":      "s3:ObjectCreated:Put, s3:ObjectRemoved:Delete",
+				"COSI_BUCKET_NOTIFICATION_PREFIX":      "images/",
+				"COSI_BUCKET_NOTIFICATION_SUFFIX":      ".jpg",
+			}
+		})
+
+		It("should configure the notifications after creating the bucket", func() {
+			var queues []types.QueueConfiguration
+			mockS3.PutBucketNotificationConfigurationFunc = func(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error) {
+				Expect(input.Bucket).To(Equal(&bucketName))
+				queues = input.NotificationConfiguration.QueueConfigurations
+				return &s3.PutBucketNotificationConfigurationOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(queues).To(Equal([]types.QueueConfiguration{{
+				Id:       aws.String("cosi-bucket-notification"),
+				QueueArn: aws.String("arn:scality:bucketnotif:::kafka-events"),
+				Events:   []types.Event{"s3:ObjectCreated:Put", "s3:ObjectRemoved:Delete"},
+				Filter: &types.NotificationConfigurationFilter{Key: &types.S3KeyFilter{FilterRules: []types.FilterRule{
+					{Name: types.FilterRuleNamePrefix, Value: aws.String("images/")},
+					{Name: types.FilterRuleNameSuffix, Value: aws.String(".jpg")},
+				}}},
+			}}))
+		})
+
+		It("should notify object creations and removals by default", func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_NOTIFICATION_DESTINATION": "arn:scality:bucketnotif:::webhook"}
+			var queue types.QueueConfiguration
+			mockS3.PutBucketNotificationConfigurationFunc = func(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error) {
+				queue = input.NotificationConfiguration.QueueConfigurations[0]
+				return &s3.PutBucketNotificationConfigurationOutput{}, nil
+			}
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(*queue.QueueArn).To(Equal("arn:scality:bucketnotif:::webhook"))
+			Expect(queue.Events).To(Equal([]types.Event{"s3:ObjectCreated:*", "s3:ObjectRemoved:*"}))
+			Expect(queue.Filter).To(BeNil())
+		})
+
+		It("should configure the notifications of a ConfigMap", func() {
+			request.Parameters = map[string]string{
+				"COSI_BUCKET_NOTIFICATION_CONFIGMAP_NAME":      "notification",
+				"COSI_BUCKET_NOTIFICATION_CONFIGMAP_NAMESPACE": "cosi-driver",
+			}
+			_, err := clientset.CoreV1().ConfigMaps("cosi-driver").Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "notification", Namespace: "cosi-driver"},
+				Data: map[string]string{"notification.json": `
+QueueConfigurations:
+- Id: created
+  QueueArn: arn:scality:bucketnotif:::kafka-events
+  Events: ["s3:ObjectCreated:*"]
+- Id: removed
+  QueueArn: arn:scality:bucketnotif:::webhook
+  Events: ["s3:ObjectRemoved:*"]
+`},
+			}, metav1.CreateOptions{})
+			Expect(err).To(BeNil())
+			var queues []types.QueueConfiguration
+			mockS3.PutBucketNotificationConfigurationFunc = func(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error) {
+				queues = input.NotificationConfiguration.QueueConfigurations
+				return &s3.PutBucketNotificationConfigurationOutput{}, nil
+			}
+
+			_, err = provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(queues).To(HaveLen(2))
+			Expect(*queues[1].QueueArn).To(Equal("arn:scality:bucketnotif:::webhook"))
+		})
+
+		It("should reconcile the notifications when the bucket is already owned by you", func() {
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				return nil, &types.BucketAlreadyOwnedByYou{}
+			}
+			configured := false
+			mockS3.PutBucketNotificationConfigurationFunc = func(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error) {
+				configured = true
+				return &s3.PutBucketNotificationConfigurationOutput{}, nil
+			}
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(configured).To(BeTrue())
+		})
+
+		It("should return FailedPrecondition error when the destination is not configured on the object storage", func() {
+			mockS3.PutBucketNotificationConfigurationFunc = func(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "InvalidArgument", Message: "Unable to validate the following destination configurations"}
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+			Expect(err.Error()).To(ContainSubstring("bucket notification destination is not configured on the object storage"))
+		})
+
+		It("should return InvalidArgument error with the message of the object storage when the configuration is malformed", func() {
+			mockS3.PutBucketNotificationConfigurationFunc = func(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "InvalidArgument", Message: "filter rule name must be either prefix or suffix"}
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring("bucket notification configuration rejected: filter rule name must be either prefix or suffix"))
+		})
+
+		It("should return Internal error for other S3 client errors", func() {
+			mockS3.PutBucketNotificationConfigurationFunc = func(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to configure bucket notifications: test-bucket"))
+		})
+
+		DescribeTable("should return InvalidArgument error for invalid notification parameters",
+			func(parameters map[string]string, message string) {
+				request.Parameters = parameters
+				mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+					Fail("CreateBucket should not be called")
+					return nil, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("missing destination",
+				map[string]string{"COSI_BUCKET_NOTIFICATION_EVENTS": "s3:ObjectCreated:*"},
+				"COSI_BUCKET_NOTIFICATION_DESTINATION is required"),
+			Entry("invalid event",
+				map[string]string{"COSI_BUCKET_NOTIFICATION_DESTINATION": "kafka-events", "COSI_BUCKET_NOTIFICATION_EVENTS": "ObjectCreated"},
+				"has an invalid event ObjectCreated"),
+			Entry("ConfigMap and parameters",
+				map[string]string{"COSI_BUCKET_NOTIFICATION_DESTINATION": "kafka-events", "COSI_BUCKET_NOTIFICATION_CONFIGMAP_NAME": "notification"},
+				"mutually exclusive"),
+		)
+	})
+
+	Context("with bucket replication", func() {
+		BeforeEach(func() {
+			request.Parameters = map[string]string{
+				"COSI_BUCKET_REPLICATION_DESTINATION_BUCKET": "{{.BucketName}}-replica",
+				"COSI_BUCKET_REPLICATION_ROLE":               "arn:aws:iam::root:role/s3-replication-role",
+			}
+		})
+
+		It("should enable versioning and replicate the whole bucket after creating it", func() {
+			var calls []string
+			mockS3.PutBucketVersioningFunc = func(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error) {
+				Expect(input.VersioningConfiguration.Status).To(Equal(types.BucketVersioningStatusEnabled))
+				calls = append(calls, "versioning")
+				return &s3.PutBucketVersioningOutput{}, nil
+			}
+			mockS3.PutBucketReplicationFunc = func(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error) {
+				Expect(input.Bucket).To(Equal(&bucketName))
+				Expect(*input.ReplicationConfiguration.Role).To(Equal("arn:aws:iam::root:role/s3-replication-role"))
+				Expect(input.ReplicationConfiguration.Rules).To(HaveLen(1))
+				rule := input.ReplicationConfiguration.Rules[0]
+				Expect(*rule.Prefix).To(BeEmpty())
+				Expect(rule.Status).To(Equal(types.ReplicationRuleStatusEnabled))
+				Expect(*rule.Destination.Bucket).To(Equal("arn:aws:s3:::test-bucket-replica"))
+				calls = append(calls, "replication")
+				return &s3.PutBucketReplicationOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(calls).To(Equal([]string{"versioning", "replication"}))
+		})
+
+		It("should generate one rule per prefix with the destination storage class", func() {
+			request.Parameters["COSI_BUCKET_REPLICATION_DESTINATION_BUCKET"] = "arn:aws:s3:::{{.BucketName}}-replica"
+			request.Parameters["COSI_BUCKET_REPLICATION_PREFIXES"] = "logs/, data/,"
+			request.Parameters["COSI_BUCKET_REPLICATION_STORAGE_CLASS"] = "remote-location"
+			var rules []types.ReplicationRule
+			mockS3.PutBucketReplicationFunc = func(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error) {
+				rules = input.ReplicationConfiguration.Rules
+				return &s3.PutBucketReplicationOutput{}, nil
+			}
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(rules).To(HaveLen(2))
+			Expect(*rules[0].ID).To(Equal("cosi-replication-0"))
+			Expect(*rules[0].Prefix).To(Equal("logs/"))
+			Expect(*rules[1].ID).To(Equal("cosi-replication-1"))
+			Expect(*rules[1].Prefix).To(Equal("data/"))
+			for _, rule := range rules {
+				Expect(*rule.Destination.Bucket).To(Equal("arn:aws:s3:::test-bucket-replica"))
+				Expect(rule.Destination.StorageClass).To(Equal(types.StorageClass("remote-location")))
+			}
+		})
+
+		It("should accept source and destination roles separated by a comma and spaces", func() {
+			request.Parameters["COSI_BUCKET_REPLICATION_ROLE"] = "arn:aws:iam::root:role/source, arn:aws:iam::root:role/destination"
+			var role string
+			mockS3.PutBucketReplicationFunc = func(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error) {
+				role = aws.ToString(input.ReplicationConfiguration.Role)
+				return &s3.PutBucketReplicationOutput{}, nil
+			}
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(role).To(Equal("arn:aws:iam::root:role/source,arn:aws:iam::root:role/destination"))
+		})
+
+		It("should create the versioned destination bucket when a destination secret is set", func() {
+			request.Parameters["COSI_BUCKET_REPLICATION_DESTINATION_SECRET_NAME"] = "remote-s3-secret"
+			request.Parameters["COSI_BUCKET_REPLICATION_DESTINATION_SECRET_NAMESPACE"] = "remote"
+			var created, versioned []string
+			driver.InitializeClient = func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*s3client.S3Client, *s3client.S3Params, error) {
+				if parameters["COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME"] == "remote-s3-secret" {
+					Expect(parameters["COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAMESPACE"]).To(Equal("remote"))
+				}
+				return &s3client.S3Client{S3Service: mockS3, QuotaService: mockQuota}, &s3Params, nil
+			}
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				created = append(created, *input.Bucket)
+				if *input.Bucket == "test-bucket-replica" {
+					return nil, &types.BucketAlreadyOwnedByYou{}
+				}
+				return &s3.CreateBucketOutput{}, nil
+			}
+			mockS3.PutBucketVersioningFunc = func(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error) {
+				versioned = append(versioned, *input.Bucket)
+				return &s3.PutBucketVersioningOutput{}, nil
+			}
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(created).To(Equal([]string{bucketName, "test-bucket-replica"}))
+			Expect(versioned).To(Equal([]string{bucketName, "test-bucket-replica"}))
+		})
+
+		It("should replicate to a bucket of the same name on the site of the destination secret", func() {
+			request.Parameters["COSI_BUCKET_REPLICATION_DESTINATION_BUCKET"] = "{{.BucketName}}"
+			request.Parameters["COSI_BUCKET_REPLICATION_DESTINATION_SECRET_NAME"] = "remote-s3-secret"
+			var destination string
+			mockS3.PutBucketReplicationFunc = func(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error) {
+				destination = *input.ReplicationConfiguration.Rules[0].Destination.Bucket
+				return &s3.PutBucketReplicationOutput{}, nil
+			}
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(destination).To(Equal("arn:aws:s3:::" + bucketName))
+		})
+
+		It("should not connect to the destination site when the bucket cannot be created", func() {
+			request.Parameters["COSI_BUCKET_REPLICATION_DESTINATION_SECRET_NAME"] = "remote-s3-secret"
+			driver.InitializeClient = func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*s3client.S3Client, *s3client.S3Params, error) {
+				if parameters["COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME"] == "remote-s3-secret" {
+					Fail("the destination S3 client should not be initialized")
+				}
+				return &s3client.S3Client{S3Service: mockS3, QuotaService: mockQuota}, &s3Params, nil
+			}
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				return nil, &types.BucketAlreadyExists{}
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+		})
+
+		It("should reconcile the replication when the bucket is already owned by you", func() {
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				return nil, &types.BucketAlreadyOwnedByYou{}
+			}
+			mockS3.GetBucketVersioningFunc = func(ctx context.Context, input *s3.GetBucketVersioningInput, opts ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+				return &s3.GetBucketVersioningOutput{Status: types.BucketVersioningStatusEnabled}, nil
+			}
+			replicated := false
+			mockS3.PutBucketReplicationFunc = func(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error) {
+				replicated = true
+				return &s3.PutBucketReplicationOutput{}, nil
+			}
+
+			_, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(replicated).To(BeTrue())
+		})
+
+		It("should return Internal error when the replication cannot be configured", func() {
+			mockS3.PutBucketReplicationFunc = func(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to configure bucket replication: test-bucket"))
+		})
+
+		DescribeTable("should return InvalidArgument error for invalid replication parameters",
+			func(parameters map[string]string, message string) {
+				request.Parameters = parameters
+				mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+					Fail("CreateBucket should not be called")
+					return nil, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("missing role",
+				map[string]string{"COSI_BUCKET_REPLICATION_DESTINATION_BUCKET": "{{.BucketName}}-replica"},
+				"COSI_BUCKET_REPLICATION_ROLE is required"),
+			Entry("role list without any role",
+				map[string]string{"COSI_BUCKET_REPLICATION_DESTINATION_BUCKET": "{{.BucketName}}-replica", "COSI_BUCKET_REPLICATION_ROLE": " , "},
+				"COSI_BUCKET_REPLICATION_ROLE is required"),
+			Entry("role that is not an IAM ARN",
+				map[string]string{"COSI_BUCKET_REPLICATION_DESTINATION_BUCKET": "{{.BucketName}}-replica", "COSI_BUCKET_REPLICATION_ROLE": "replication-role"},
+				"invalid COSI_BUCKET_REPLICATION_ROLE value"),
+			Entry("object key as destination",
+				map[string]string{"COSI_BUCKET_REPLICATION_DESTINATION_BUCKET": "arn:aws:s3:::{{.BucketName}}/key", "COSI_BUCKET_REPLICATION_ROLE": "arn:aws:iam::root:role/r"},
+				"invalid COSI_BUCKET_REPLICATION_DESTINATION_BUCKET value"),
+			Entry("destination shared by the BucketClass",
+				map[string]string{"COSI_BUCKET_REPLICATION_DESTINATION_BUCKET": "dest", "COSI_BUCKET_REPLICATION_ROLE": "arn:aws:iam::root:role/r"},
+				"must be a template using {{.BucketName}}"),
+			Entry("replicated bucket as destination",
+				map[string]string{"COSI_BUCKET_REPLICATION_DESTINATION_BUCKET": "{{.BucketName}}", "COSI_BUCKET_REPLICATION_ROLE": "arn:aws:iam::root:role/r"},
+				"renders to the replicated bucket test-bucket"),
+			Entry("malformed destination template",
+				map[string]string{"COSI_BUCKET_REPLICATION_DESTINATION_BUCKET": "{{.BucketName", "COSI_BUCKET_REPLICATION_ROLE": "arn:aws:iam::root:role/r"},
+				"invalid COSI_BUCKET_REPLICATION_DESTINATION_BUCKET"),
+			Entry("parameters without a destination",
+				map[string]string{"COSI_BUCKET_REPLICATION_ROLE": "arn:aws:iam::root:role/r"},
+				"COSI_BUCKET_REPLICATION_ROLE requires COSI_BUCKET_REPLICATION_DESTINATION_BUCKET"),
+			Entry("suspended versioning",
+				map[string]string{"COSI_BUCKET_REPLICATION_DESTINATION_BUCKET": "{{.BucketName}}-replica", "COSI_BUCKET_REPLICATION_ROLE": "arn:aws:iam::root:role/r", "COSI_BUCKET_VERSIONING": "Suspended"},
+				"replication requires versioning"),
+		)
+	})
+
+	Context("with COSI_BUCKET_QUOTA", func() {
+		BeforeEach(func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_QUOTA": "500Gi"}
+		})
+
+		It("should set the bucket quota in bytes after creating the bucket", func() {
+			var quota int64
+			mockQuota.PutBucketQuotaFunc = func(ctx context.Context, name string, value int64) error {
+				Expect(name).To(Equal(bucketName))
+				quota = value
+				return nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(quota).To(Equal(int64(500 * 1024 * 1024 * 1024)))
+		})
+
+		It("should update the quota when the bucket is already owned by you", func() {
+			request.Parameters["COSI_BUCKET_QUOTA"] = "1000000"
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				return nil, &types.BucketAlreadyOwnedByYou{}
+			}
+			var quota int64
+			mockQuota.PutBucketQuotaFunc = func(ctx context.Context, name string, value int64) error {
+				quota = value
+				return nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(quota).To(Equal(int64(1000000)))
+		})
+
+		It("should return Internal error when the quota cannot be set", func() {
+			mockQuota.PutBucketQuotaFunc = func(ctx context.Context, name string, value int64) error {
+				return errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to set bucket quota: test-bucket"))
+		})
+
+		DescribeTable("should return InvalidArgument error for malformed sizes",
+			func(quota string) {
+				request.Parameters["COSI_BUCKET_QUOTA"] = quota
+				mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+					Fail("CreateBucket should not be called")
+					return nil, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring("invalid COSI_BUCKET_QUOTA value: " + quota))
+			},
+			Entry("unknown suffix", "500GB"),
+			Entry("not a number", "large"),
+			Entry("zero", "0"),
+			Entry("negative", "-1Gi"),
+		)
+	})
+
+	Context("with bucket tags", func() {
+		It("should tag the bucket with the provisioner and the COSI bucket", func() {
+			var tagSet []types.Tag
+			mockS3.PutBucketTaggingFunc = func(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+				Expect(input.Bucket).To(Equal(&bucketName))
+				tagSet = input.Tagging.TagSet
+				return &s3.PutBucketTaggingOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(tagSet).To(Equal([]types.Tag{
+				{Key: aws.String("cosi.scality.com/bucket"), Value: aws.String("test-bucket")},
+				{Key: aws.String("cosi.scality.com/provisioner"), Value: aws.String("test-provisioner")},
+			}))
+		})
+
+		It("should add the static tags of the BucketClass", func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_TAGS": "team=payments, env=prod"}
+			var tagSet []types.Tag
+			mockS3.PutBucketTaggingFunc = func(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+				tagSet = input.Tagging.TagSet
+				return &s3.PutBucketTaggingOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(tagSet).To(ContainElements(
+				types.Tag{Key: aws.String("env"), Value: aws.String("prod")},
+				types.Tag{Key: aws.String("team"), Value: aws.String("payments")},
+			))
+			Expect(tagSet).To(HaveLen(4))
+		})
+
+		It("should reconcile the tags when the bucket is already owned by you", func() {
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				return nil, &types.BucketAlreadyOwnedByYou{}
+			}
+			tagged := false
+			mockS3.PutBucketTaggingFunc = func(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+				tagged = true
+				return &s3.PutBucketTaggingOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(tagged).To(BeTrue())
+		})
+
+		It("should return Internal error when the bucket cannot be tagged", func() {
+			mockS3.PutBucketTaggingFunc = func(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to tag bucket: test-bucket"))
+		})
+
+		DescribeTable("should return InvalidArgument error for invalid tags",
+			func(tags string, message string) {
+				request.Parameters = map[string]string{"COSI_BUCKET_TAGS": tags}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("missing value separator", "team", "must be key=value"),
+			Entry("empty key", "=payments", "must be key=value"),
+			Entry("reserved prefix", "cosi.scality.com/bucket=other", "prefix is reserved"),
+			Entry("duplicate key", "team=a,team=b", "duplicate COSI_BUCKET_TAGS key: team"),
+			Entry("key too long", strings.Repeat("k", 129)+"=v", "keys are limited to 128 characters"),
+			Entry("too many tags", generateTags(49), "too many bucket tags: 51"),
+		)
+	})
+
+	Context("with lifecycle parameters", func() {
+		BeforeEach(func() {
+			request.Parameters = map[string]string{
+				"COSI_BUCKET_LIFECYCLE_EXPIRATION_DAYS":              "30",
+				"COSI_BUCKET_LIFECYCLE_NONCURRENT_EXPIRATION_DAYS":   "7",
+				"COSI_BUCKET_LIFECYCLE_ABORT_INCOMPLETE_UPLOAD_DAYS": "1",
+				"COSI_BUCKET_LIFECYCLE_TRANSITION_DAYS":              "10",
+				"COSI_BUCKET_LIFECYCLE_TRANSITION_STORAGE_CLASS":     "cold-location",
+			}
+		})
+
+		It("should configure a lifecycle rule for the whole bucket", func() {
+			var lifecycle *types.BucketLifecycleConfiguration
+			mockS3.PutBucketLifecycleConfigurationFunc = func(ctx context.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+				Expect(input.Bucket).To(Equal(&bucketName))
+				lifecycle = input.LifecycleConfiguration
+				return &s3.PutBucketLifecycleConfigurationOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(lifecycle.Rules).To(HaveLen(1))
+			rule := lifecycle.Rules[0]
+			Expect(rule.Status).To(Equal(types.ExpirationStatusEnabled))
+			Expect(rule.Filter.Prefix).To(Equal(aws.String("")))
+			Expect(rule.Expiration.Days).To(Equal(aws.Int32(30)))
+			Expect(rule.NoncurrentVersionExpiration.NoncurrentDays).To(Equal(aws.Int32(7)))
+			Expect(rule.AbortIncompleteMultipartUpload.DaysAfterInitiation).To(Equal(aws.Int32(1)))
+			Expect(rule.Transitions).To(Equal([]types.Transition{{
+				Days:         aws.Int32(10),
+				StorageClass: types.TransitionStorageClass("cold-location"),
+			}}))
+		})
+
+		It("should reconcile the lifecycle rules when the bucket is already owned by you", func() {
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				return nil, &types.BucketAlreadyOwnedByYou{}
+			}
+			configured := false
+			mockS3.PutBucketLifecycleConfigurationFunc = func(ctx context.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+				configured = true
+				return &s3.PutBucketLifecycleConfigurationOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(configured).To(BeTrue())
+		})
+
+		It("should return Internal error when the lifecycle cannot be configured", func() {
+			mockS3.PutBucketLifecycleConfigurationFunc = func(ctx context.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to configure bucket lifecycle: test-bucket"))
+		})
+
+		DescribeTable("should return InvalidArgument error for inconsistent settings",
+			func(parameters map[string]string, message string) {
+				request.Parameters = parameters
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("invalid number of days",
+				map[string]string{"COSI_BUCKET_LIFECYCLE_EXPIRATION_DAYS": "thirty"},
+				"invalid COSI_BUCKET_LIFECYCLE_EXPIRATION_DAYS value: thirty"),
+			Entry("transition without storage class",
+				map[string]string{"COSI_BUCKET_LIFECYCLE_TRANSITION_DAYS": "10"},
+				"must be set together"),
+			Entry("rule parameters with a ConfigMap",
+				map[string]string{"COSI_BUCKET_LIFECYCLE_EXPIRATION_DAYS": "30", "COSI_BUCKET_LIFECYCLE_CONFIGMAP_NAME": "lifecycle"},
+				"mutually exclusive"),
+		)
+
+		Context("from a ConfigMap", func() {
+			BeforeEach(func() {
+				request.Parameters = map[string]string{
+					"COSI_BUCKET_LIFECYCLE_CONFIGMAP_NAME":      "lifecycle",
+					"COSI_BUCKET_LIFECYCLE_CONFIGMAP_NAMESPACE": "cosi-driver",
+				}
+			})
+
+			createConfigMap := func(document string) {
+				_, err := clientset.CoreV1().ConfigMaps("cosi-driver").Create(ctx, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "lifecycle", Namespace: "cosi-driver"},
+					Data:       map[string]string{"lifecycle.json": document},
+				}, metav1.CreateOptions{})
+				Expect(err).To(BeNil())
+			}
+
+			It("should configure the rules of the lifecycle document", func() {
+				createConfigMap(`
+Rules:
+- ID: expire-logs
+  Status: Enabled
+  Filter:
+    Prefix: logs/
+  Expiration:
+    Days: 14
+- ID: cleanup-uploads
+  Status: Enabled
+  AbortIncompleteMultipartUpload:
+    DaysAfterInitiation: 2
+`)
+				var lifecycle *types.BucketLifecycleConfiguration
+				mockS3.PutBucketLifecycleConfigurationFunc = func(ctx context.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+					lifecycle = input.LifecycleConfiguration
+					return &s3.PutBucketLifecycleConfigurationOutput{}, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(err).To(BeNil())
+				Expect(resp.BucketId).To(Equal(bucketName))
+				Expect(lifecycle.Rules).To(HaveLen(2))
+				Expect(lifecycle.Rules[0].ID).To(Equal(aws.String("expire-logs")))
+				Expect(lifecycle.Rules[0].Filter.Prefix).To(Equal(aws.String("logs/")))
+				Expect(lifecycle.Rules[0].Expiration.Days).To(Equal(aws.Int32(14)))
+				Expect(lifecycle.Rules[1].Filter.Prefix).To(Equal(aws.String("")))
+				Expect(lifecycle.Rules[1].AbortIncompleteMultipartUpload.DaysAfterInitiation).To(Equal(aws.Int32(2)))
+			})
+
+			It("should accept a JSON lifecycle document", func() {
+				createConfigMap(`{"Rules":[{"ID":"expire","Status":"Enabled","Filter":{"Prefix":""},"Expiration":{"Days":1}}]}`)
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(err).To(BeNil())
+				Expect(resp.BucketId).To(Equal(bucketName))
+			})
+
+			It("should return InvalidArgument error for an unknown field", func() {
+				createConfigMap(`{"Rules":[{"ID":"expire","Status":"Enabled","Expire":{"Days":1}}]}`)
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring("invalid lifecycle configuration"))
+			})
+
+			It("should return InvalidArgument error for an invalid rule status", func() {
+				createConfigMap(`{"Rules":[{"ID":"expire","Status":"On","Expiration":{"Days":1}}]}`)
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring("rule 0 status must be Enabled or Disabled"))
+			})
+
+			It("should return InvalidArgument error when the ConfigMap does not exist", func() {
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring("lifecycle ConfigMap cosi-driver/lifecycle not found"))
+			})
+		})
+	})
+
+	Context("with COSI_BUCKET_ENCRYPTION", func() {
+		BeforeEach(func() {
+			request.Parameters = map[string]string{
+				"COSI_BUCKET_ENCRYPTION":            "aws:kms",
+				"COSI_BUCKET_ENCRYPTION_KMS_KEY_ID": "test-key-id",
+			}
+		})
+
+		It("should configure default encryption after creating the bucket", func() {
+			configured := false
+			mockS3.PutBucketEncryptionFunc = func(ctx context.Context, input *s3.PutBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.PutBucketEncryptionOutput, error) {
+				Expect(input.Bucket).To(Equal(&bucketName))
+				Expect(input.ServerSideEncryptionConfiguration.Rules).To(HaveLen(1))
+				Expect(input.ServerSideEncryptionConfiguration.Rules[0].ApplyServerSideEncryptionByDefault).To(Equal(&types.ServerSideEncryptionByDefault{
+					SSEAlgorithm:   types.ServerSideEncryptionAwsKms,
+					KMSMasterKeyID: aws.String("test-key-id"),
+				}))
+				configured = true
+				return &s3.PutBucketEncryptionOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(configured).To(BeTrue())
+		})
+
+		DescribeTable("should return InvalidArgument error for inconsistent settings",
+			func(parameters map[string]string, message string) {
+				request.Parameters = parameters
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("unknown algorithm",
+				map[string]string{"COSI_BUCKET_ENCRYPTION": "aws:kms:dsse"},
+				"invalid COSI_BUCKET_ENCRYPTION value: aws:kms:dsse"),
+			Entry("key ID with AES256",
+				map[string]string{"COSI_BUCKET_ENCRYPTION": "AES256", "COSI_BUCKET_ENCRYPTION_KMS_KEY_ID": "test-key-id"},
+				"cannot be used with AES256 encryption"),
+			Entry("key ID without algorithm",
+				map[string]string{"COSI_BUCKET_ENCRYPTION_KMS_KEY_ID": "test-key-id"},
+				"COSI_BUCKET_ENCRYPTION_KMS_KEY_ID requires COSI_BUCKET_ENCRYPTION to be aws:kms"),
+		)
+
+		It("should return Internal error when encryption cannot be configured", func() {
+			mockS3.PutBucketEncryptionFunc = func(ctx context.Context, input *s3.PutBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.PutBucketEncryptionOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to configure bucket encryption: test-bucket"))
+		})
+
+		Context("when the bucket is already owned by you", func() {
+			BeforeEach(func() {
+				mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+					return nil, &types.BucketAlreadyOwnedByYou{}
+				}
+			})
+
+			It("should return success if encryption matches", func() {
+				mockS3.GetBucketEncryptionFunc = func(ctx context.Context, input *s3.GetBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+					return &s3.GetBucketEncryptionOutput{ServerSideEncryptionConfiguration: &types.ServerSideEncryptionConfiguration{
+						Rules: []types.ServerSideEncryptionRule{{ApplyServerSideEncryptionByDefault: &types.ServerSideEncryptionByDefault{
+							SSEAlgorithm:   types.ServerSideEncryptionAwsKms,
+							KMSMasterKeyID: aws.String("test-key-id"),
+						}}},
+					}}, nil
+				}
+				mockS3.PutBucketEncryptionFunc = func(ctx context.Context, input *s3.PutBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.PutBucketEncryptionOutput, error) {
+					Fail("PutBucketEncryption should not be called")
+					return nil, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(err).To(BeNil())
+				Expect(resp.BucketId).To(Equal(bucketName))
+			})
+
+			It("should configure encryption if the bucket has none", func() {
+				configured := false
+				mockS3.GetBucketEncryptionFunc = func(ctx context.Context, input *s3.GetBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+					return nil, &smithy.GenericAPIError{Code: "ServerSideEncryptionConfigurationNotFoundError"}
+				}
+				mockS3.PutBucketEncryptionFunc = func(ctx context.Context, input *s3.PutBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.PutBucketEncryptionOutput, error) {
+					configured = true
+					return &s3.PutBucketEncryptionOutput{}, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(err).To(BeNil())
+				Expect(resp.BucketId).To(Equal(bucketName))
+				Expect(configured).To(BeTrue())
+			})
+
+			It("should return AlreadyExists error if encryption differs", func() {
+				mockS3.GetBucketEncryptionFunc = func(ctx context.Context, input *s3.GetBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+					return &s3.GetBucketEncryptionOutput{ServerSideEncryptionConfiguration: &types.ServerSideEncryptionConfiguration{
+						Rules: []types.ServerSideEncryptionRule{{ApplyServerSideEncryptionByDefault: &types.ServerSideEncryptionByDefault{
+							SSEAlgorithm: types.ServerSideEncryptionAes256,
+						}}},
+					}}, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+				Expect(err.Error()).To(ContainSubstring("Bucket already exists with different parameters: test-bucket: encryption is AES256, requested aws:kms"))
+			})
+		})
+	})
+
+	Context("with COSI_BUCKET_OBJECT_LOCK_ENABLED", func() {
+		BeforeEach(func() {
+			request.Parameters = map[string]string{
+				"COSI_BUCKET_OBJECT_LOCK_ENABLED":         "true",
+				"COSI_BUCKET_OBJECT_LOCK_MODE":            "COMPLIANCE",
+				"COSI_BUCKET_OBJECT_LOCK_RETENTION_YEARS": "7",
+			}
+		})
+
+		It("should create the bucket with object lock and set the default retention", func() {
+			var calls []string
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				Expect(input.ObjectLockEnabledForBucket).To(Equal(aws.Bool(true)))
+				calls = append(calls, "CreateBucket")
+				return &s3.CreateBucketOutput{}, nil
+			}
+			mockS3.PutObjectLockConfigurationFunc = func(ctx context.Context, input *s3.PutObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.PutObjectLockConfigurationOutput, error) {
+				Expect(input.ObjectLockConfiguration.Rule.DefaultRetention).To(Equal(&types.DefaultRetention{
+					Mode:  types.ObjectLockRetentionModeCompliance,
+					Years: aws.Int32(7),
+				}))
+				calls = append(calls, "PutObjectLockConfiguration")
+				return &s3.PutObjectLockConfigurationOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(calls).To(Equal([]string{"CreateBucket", "PutObjectLockConfiguration"}))
+		})
+
+		It("should not set a retention rule when only object lock is enabled", func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_OBJECT_LOCK_ENABLED": "true"}
+			mockS3.PutObjectLockConfigurationFunc = func(ctx context.Context, input *s3.PutObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.PutObjectLockConfigurationOutput, error) {
+				Fail("PutObjectLockConfiguration should not be called")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+		})
+
+		DescribeTable("should return InvalidArgument error for inconsistent settings",
+			func(parameters map[string]string, message string) {
+				request.Parameters = parameters
+				mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+					Fail("CreateBucket should not be called")
+					return nil, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring(message))
+			},
+			Entry("invalid enabled flag",
+				map[string]string{"COSI_BUCKET_OBJECT_LOCK_ENABLED": "yes please"},
+				"invalid COSI_BUCKET_OBJECT_LOCK_ENABLED value"),
+			Entry("retention without object lock",
+				map[string]string{"COSI_BUCKET_OBJECT_LOCK_MODE": "GOVERNANCE", "COSI_BUCKET_OBJECT_LOCK_RETENTION_DAYS": "30"},
+				"object lock retention requires COSI_BUCKET_OBJECT_LOCK_ENABLED to be true"),
+			Entry("invalid mode",
+				map[string]string{"COSI_BUCKET_OBJECT_LOCK_ENABLED": "true", "COSI_BUCKET_OBJECT_LOCK_MODE": "LEGAL_HOLD", "COSI_BUCKET_OBJECT_LOCK_RETENTION_DAYS": "30"},
+				"invalid COSI_BUCKET_OBJECT_LOCK_MODE value: LEGAL_HOLD"),
+			Entry("period without mode",
+				map[string]string{"COSI_BUCKET_OBJECT_LOCK_ENABLED": "true", "COSI_BUCKET_OBJECT_LOCK_RETENTION_DAYS": "30"},
+				"COSI_BUCKET_OBJECT_LOCK_MODE is required"),
+			Entry("mode without period",
+				map[string]string{"COSI_BUCKET_OBJECT_LOCK_ENABLED": "true", "COSI_BUCKET_OBJECT_LOCK_MODE": "GOVERNANCE"},
+				"COSI_BUCKET_OBJECT_LOCK_RETENTION_DAYS or COSI_BUCKET_OBJECT_LOCK_RETENTION_YEARS is required"),
+			Entry("both days and years",
+				map[string]string{"COSI_BUCKET_OBJECT_LOCK_ENABLED": "true", "COSI_BUCKET_OBJECT_LOCK_MODE": "GOVERNANCE",
+					"COSI_BUCKET_OBJECT_LOCK_RETENTION_DAYS": "30", "COSI_BUCKET_OBJECT_LOCK_RETENTION_YEARS": "1"},
+				"mutually exclusive"),
+			Entry("non positive period",
+				map[string]string{"COSI_BUCKET_OBJECT_LOCK_ENABLED": "true", "COSI_BUCKET_OBJECT_LOCK_MODE": "GOVERNANCE", "COSI_BUCKET_OBJECT_LOCK_RETENTION_DAYS": "0"},
+				"invalid COSI_BUCKET_OBJECT_LOCK_RETENTION_DAYS value: 0"),
+			Entry("suspended versioning",
+				map[string]string{"COSI_BUCKET_OBJECT_LOCK_ENABLED": "true", "COSI_BUCKET_VERSIONING": "Suspended"},
+				"object lock requires versioning"),
+		)
+
+		Context("when the bucket is already owned by you", func() {
+			BeforeEach(func() {
+				mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+					return nil, &types.BucketAlreadyOwnedByYou{}
+				}
+			})
+
+			It("should return success if the retention matches", func() {
+				mockS3.GetObjectLockConfigurationFunc = func(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+					return &s3.GetObjectLockConfigurationOutput{ObjectLockConfiguration: &types.ObjectLockConfiguration{
+						ObjectLockEnabled: types.ObjectLockEnabledEnabled,
+						Rule: &types.ObjectLockRule{DefaultRetention: &types.DefaultRetention{
+							Mode:  types.ObjectLockRetentionModeCompliance,
+							Years: aws.Int32(7),
+						}},
+					}}, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(err).To(BeNil())
+				Expect(resp.BucketId).To(Equal(bucketName))
+			})
+
+			It("should set the retention if the bucket has none", func() {
+				configured := false
+				mockS3.GetObjectLockConfigurationFunc = func(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+					return &s3.GetObjectLockConfigurationOutput{ObjectLockConfiguration: &types.ObjectLockConfiguration{
+						ObjectLockEnabled: types.ObjectLockEnabledEnabled,
+					}}, nil
+				}
+				mockS3.PutObjectLockConfigurationFunc = func(ctx context.Context, input *s3.PutObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.PutObjectLockConfigurationOutput, error) {
+					configured = true
+					return &s3.PutObjectLockConfigurationOutput{}, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(err).To(BeNil())
+				Expect(resp.BucketId).To(Equal(bucketName))
+				Expect(configured).To(BeTrue())
+			})
+
+			It("should return AlreadyExists error if the bucket has no object lock", func() {
+				mockS3.GetObjectLockConfigurationFunc = func(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+					return nil, &smithy.GenericAPIError{Code: "ObjectLockConfigurationNotFoundError"}
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+				Expect(err.Error()).To(ContainSubstring("Bucket already exists with different parameters: test-bucket: object lock is disabled, requested enabled"))
+			})
+
+			It("should return AlreadyExists error if the retention differs", func() {
+				mockS3.GetObjectLockConfigurationFunc = func(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+					return &s3.GetObjectLockConfigurationOutput{ObjectLockConfiguration: &types.ObjectLockConfiguration{
+						ObjectLockEnabled: types.ObjectLockEnabledEnabled,
+						Rule: &types.ObjectLockRule{DefaultRetention: &types.DefaultRetention{
+							Mode: types.ObjectLockRetentionModeGovernance,
+							Days: aws.Int32(30),
+						}},
+					}}, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+				Expect(err.Error()).To(ContainSubstring("object lock retention is GOVERNANCE for 30 days, requested COMPLIANCE for 7 years"))
+			})
+		})
+	})
+
+	Context("with COSI_BUCKET_VERSIONING", func() {
+		BeforeEach(func() {
+			request.Parameters = map[string]string{"COSI_BUCKET_VERSIONING": "Enabled"}
+		})
+
+		It("should enable versioning after creating the bucket", func() {
+			var calls []string
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				calls = append(calls, "CreateBucket")
+				return &s3.CreateBucketOutput{}, nil
+			}
+			mockS3.PutBucketVersioningFunc = func(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error) {
+				Expect(input.Bucket).To(Equal(&bucketName))
+				Expect(input.VersioningConfiguration.Status).To(Equal(types.BucketVersioningStatusEnabled))
+				calls = append(calls, "PutBucketVersioning")
+				return &s3.PutBucketVersioningOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.BucketId).To(Equal(bucketName))
+			Expect(calls).To(Equal([]string{"CreateBucket", "PutBucketVersioning"}))
+		})
+
+		It("should return InvalidArgument error for an invalid value without creating the bucket", func() {
+			request.Parameters["COSI_BUCKET_VERSIONING"] = "enabled"
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				Fail("CreateBucket should not be called")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring("invalid COSI_BUCKET_VERSIONING value: enabled"))
+		})
+
+		It("should return Internal error when versioning cannot be configured", func() {
+			mockS3.PutBucketVersioningFunc = func(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverCreateBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to configure bucket versioning: test-bucket"))
+		})
+
+		Context("when the bucket is already owned by you", func() {
+			BeforeEach(func() {
+				mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+					return nil, &types.BucketAlreadyOwnedByYou{}
+				}
+			})
+
+			It("should return success if versioning matches", func() {
+				mockS3.GetBucketVersioningFunc = func(ctx context.Context, input *s3.GetBucketVersioningInput, opts ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+					return &s3.GetBucketVersioningOutput{Status: types.BucketVersioningStatusEnabled}, nil
+				}
+				mockS3.PutBucketVersioningFunc = func(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error) {
+					Fail("PutBucketVersioning should not be called")
+					return nil, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(err).To(BeNil())
+				Expect(resp.BucketId).To(Equal(bucketName))
+			})
+
+			It("should enable versioning if it was never configured", func() {
+				configured := false
+				mockS3.PutBucketVersioningFunc = func(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error) {
+					configured = true
+					return &s3.PutBucketVersioningOutput{}, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(err).To(BeNil())
+				Expect(resp.BucketId).To(Equal(bucketName))
+				Expect(configured).To(BeTrue())
+			})
+
+			It("should return AlreadyExists error if versioning differs", func() {
+				mockS3.GetBucketVersioningFunc = func(ctx context.Context, input *s3.GetBucketVersioningInput, opts ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+					return &s3.GetBucketVersioningOutput{Status: types.BucketVersioningStatusSuspended}, nil
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+				Expect(err.Error()).To(ContainSubstring("Bucket already exists with different parameters: test-bucket: versioning is Suspended, requested Enabled"))
+			})
+
+			It("should return Internal error when versioning cannot be read", func() {
+				mockS3.GetBucketVersioningFunc = func(ctx context.Context, input *s3.GetBucketVersioningInput, opts ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+					return nil, errors.New("SomeOtherError: Something went wrong")
+				}
+
+				resp, err := provisioner.DriverCreateBucket(ctx, request)
+				Expect(resp).To(BeNil())
+				Expect(err).To(HaveOccurred())
+				Expect(status.Code(err)).To(Equal(codes.Internal))
+			})
+		})
+	})
+})
+
+var _ = Describe("ProvisionerServer DriverDeleteBucket", func() {
+	var (
+		mockS3                        *MockS3Client
+		provisioner                   *driver.ProvisionerServer
+		ctx                           context.Context
+		clientset                     *fake.Clientset
+		bucketName                    string
+		bucketParameters              map[string]string
+		s3Params                      s3client.S3Params
+		request                       *cosiapi.DriverDeleteBucketRequest
+		originalInitializeClient      func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*s3client.S3Client, *s3client.S3Params, error)
+		originalFetchBucketParameters func(ctx context.Context, s *driver.ProvisionerServer, bucketName string) (map[string]string, error)
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		mockS3 = &MockS3Client{}
+		clientset = fake.NewSimpleClientset()
+		bucketName = "test-bucket"
+		bucketParameters = map[string]string{"COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME": "test-secret"}
+		provisioner = &driver.ProvisionerServer{
+			Provisioner: "test-provisioner",
+			Clientset:   clientset,
+		}
+		s3Params = s3client.S3Params{
+			AccessKey: "test-access-key",
+			SecretKey: "test-secret-key",
+			Endpoint:  "https://test-endpoint",
+			Region:    "us-west-2",
+		}
+		request = &cosiapi.DriverDeleteBucketRequest{BucketId: bucketName}
+
+		originalInitializeClient = driver.InitializeClient
+		originalFetchBucketParameters = driver.FetchBucketParameters
+	})
+
+	AfterEach(func() {
+		driver.InitializeClient = originalInitializeClient
+		driver.FetchBucketParameters = originalFetchBucketParameters
+	})
+
+	JustBeforeEach(func() {
+		driver.FetchBucketParameters = func(ctx context.Context, s *driver.ProvisionerServer, name string) (map[string]string, error) {
+			Expect(name).To(Equal(bucketName))
+			return bucketParameters, nil
+		}
+		driver.InitializeClient = func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*s3client.S3Client, *s3client.S3Params, error) {
+			Expect(parameters).To(HaveKeyWithValue("COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME", "test-secret"))
+			return &s3client.S3Client{S3Service: mockS3}, &s3Params, nil
+		}
+	})
+
+	It("should successfully delete an existing bucket", func() {
+		mockS3.DeleteBucketFunc = func(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+			Expect(input.Bucket).To(Equal(&bucketName))
+			return &s3.DeleteBucketOutput{}, nil
+		}
+
+		resp, err := provisioner.DriverDeleteBucket(ctx, request)
+		Expect(err).To(BeNil())
+		Expect(resp).NotTo(BeNil())
+	})
+
+	It("should return success if the bucket does not exist", func() {
+		mockS3.DeleteBucketFunc = func(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+			return nil, &types.NoSuchBucket{}
+		}
+
+		resp, err := provisioner.DriverDeleteBucket(ctx, request)
+		Expect(err).To(BeNil())
+		Expect(resp).NotTo(BeNil())
+	})
+
+	It("should return FailedPrecondition error if the bucket is not empty", func() {
+		mockS3.DeleteBucketFunc = func(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+			return nil, &smithy.GenericAPIError{Code: "BucketNotEmpty", Message: "The bucket you tried to delete is not empty"}
+		}
+
+		resp, err := provisioner.DriverDeleteBucket(ctx, request)
+		Expect(resp).To(BeNil())
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		Expect(err.Error()).To(ContainSubstring("Bucket is not empty: test-bucket"))
+	})
+
+	It("should return Internal error for other S3 client errors", func() {
+		mockS3.DeleteBucketFunc = func(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+			return nil, errors.New("SomeOtherError: Something went wrong")
+		}
+
+		resp, err := provisioner.DriverDeleteBucket(ctx, request)
+		Expect(resp).To(BeNil())
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+		Expect(err.Error()).To(ContainSubstring("Failed to delete bucket"))
+	})
+
+	It("should return Internal error when the S3 client cannot be initialized", func() {
+		driver.InitializeClient = func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*s3client.S3Client, *s3client.S3Params, error) {
+			return nil, nil, errors.New("initialization failed")
+		}
+
+		resp, err := provisioner.DriverDeleteBucket(ctx, request)
+		Expect(resp).To(BeNil())
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+		Expect(err.Error()).To(ContainSubstring("failed to initialize object storage provider S3 client"))
+	})
+
+	It("should return the error when the bucket parameters cannot be fetched", func() {
+		driver.FetchBucketParameters = func(ctx context.Context, s *driver.ProvisionerServer, name string) (map[string]string, error) {
+			return nil, status.Errorf(codes.NotFound, "bucket object %s not found", name)
+		}
+
+		resp, err := provisioner.DriverDeleteBucket(ctx, request)
+		Expect(resp).To(BeNil())
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+		Expect(err.Error()).To(ContainSubstring("bucket object test-bucket not found"))
+	})
+
+	Context("with an adopted bucket", func() {
+		BeforeEach(func() {
+			bucketParameters = map[string]string{"COSI_EXISTING_BUCKET_NAME": bucketName}
+		})
+
+		It("should keep a bucket adopted through COSI_EXISTING_BUCKET_NAME", func() {
+			mockS3.DeleteBucketFunc = func(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+				Fail("DeleteBucket should not be called")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverDeleteBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp).NotTo(BeNil())
+		})
+
+		It("should keep a statically provisioned bucket", func(specCtx SpecContext) {
+			bucketClientset := bucketfake.NewSimpleClientset(&bucketv1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: "static-bucket"},
+				Spec:       bucketv1alpha1.BucketSpec{ExistingBucketID: bucketName},
+				Status:     bucketv1alpha1.BucketStatus{BucketID: bucketName},
+			})
+			informer, err := driver.WatchBucketObjects(specCtx, bucketClientset)
+			Expect(err).To(BeNil())
+			provisioner.BucketClientset = bucketClientset
+			provisioner.BucketInformer = informer
+			driver.FetchBucketParameters = originalFetchBucketParameters
+			mockS3.DeleteBucketFunc = func(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+				Fail("DeleteBucket should not be called")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverDeleteBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp).NotTo(BeNil())
+		})
+
+		It("should delete the bucket when COSI_EXISTING_BUCKET_DELETE allows it", func() {
+			bucketParameters["COSI_EXISTING_BUCKET_DELETE"] = "true"
+			bucketParameters["COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME"] = "test-secret"
+			deleted := false
+			mockS3.DeleteBucketFunc = func(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+				deleted = true
+				return &s3.DeleteBucketOutput{}, nil
+			}
+
+			_, err := provisioner.DriverDeleteBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(deleted).To(BeTrue())
+		})
+
+		It("should return InvalidArgument error for an invalid COSI_EXISTING_BUCKET_DELETE value", func() {
+			bucketParameters["COSI_EXISTING_BUCKET_DELETE"] = "maybe"
+
+			resp, err := provisioner.DriverDeleteBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring("invalid COSI_EXISTING_BUCKET_DELETE value: maybe"))
+		})
+	})
+
+	Context("with COSI_BUCKET_FORCE_DELETE", func() {
+		BeforeEach(func() {
+			bucketParameters = map[string]string{"COSI_BUCKET_FORCE_DELETE": "true"}
+		})
+
+		JustBeforeEach(func() {
+			driver.InitializeClient = func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*s3client.S3Client, *s3client.S3Params, error) {
+				return &s3client.S3Client{S3Service: mockS3}, &s3Params, nil
+			}
+		})
+
+		It("should purge uploads and object versions before deleting the bucket", func() {
+			var calls []string
+			mockS3.ListMultipartUploadsFunc = func(ctx context.Context, input *s3.ListMultipartUploadsInput, opts ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error) {
+				calls = append(calls, "ListMultipartUploads")
+				return &s3.ListMultipartUploadsOutput{
+					Uploads: []types.MultipartUpload{{Key: aws.String("key"), UploadId: aws.String("upload")}},
+				}, nil
+			}
+			mockS3.AbortMultipartUploadFunc = func(ctx context.Context, input *s3.AbortMultipartUploadInput, opts ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+				calls = append(calls, "AbortMultipartUpload")
+				return &s3.AbortMultipartUploadOutput{}, nil
+			}
+			mockS3.ListObjectVersionsFunc = func(ctx context.Context, input *s3.ListObjectVersionsInput, opts ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error) {
+				calls = append(calls, "ListObjectVersions")
+				return &s3.ListObjectVersionsOutput{
+					Versions: []types.ObjectVersion{{Key: aws.String("key"), VersionId: aws.String("v1")}},
+				}, nil
+			}
+			mockS3.DeleteObjectsFunc = func(ctx context.Context, input *s3.DeleteObjectsInput, opts ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+				calls = append(calls, "DeleteObjects")
+				return &s3.DeleteObjectsOutput{}, nil
+			}
+			mockS3.DeleteBucketFunc = func(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+				calls = append(calls, "DeleteBucket")
+				return &s3.DeleteBucketOutput{}, nil
+			}
+
+			resp, err := provisioner.DriverDeleteBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp).NotTo(BeNil())
+			Expect(calls).To(Equal([]string{"ListMultipartUploads", "AbortMultipartUpload", "ListObjectVersions", "DeleteObjects", "DeleteBucket"}))
+		})
+
+		It("should not purge the bucket when the parameter is false", func() {
+			bucketParameters["COSI_BUCKET_FORCE_DELETE"] = "false"
+			mockS3.ListObjectVersionsFunc = func(ctx context.Context, input *s3.ListObjectVersionsInput, opts ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error) {
+				Fail("ListObjectVersions should not be called")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverDeleteBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp).NotTo(BeNil())
+		})
+
+		It("should return InvalidArgument error for an invalid value", func() {
+			bucketParameters["COSI_BUCKET_FORCE_DELETE"] = "maybe"
+
+			resp, err := provisioner.DriverDeleteBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring("invalid COSI_BUCKET_FORCE_DELETE value: maybe"))
+		})
+
+		It("should return success if the bucket disappears during the purge", func() {
+			mockS3.ListMultipartUploadsFunc = func(ctx context.Context, input *s3.ListMultipartUploadsInput, opts ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "NoSuchBucket"}
+			}
+			mockS3.DeleteBucketFunc = func(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+				return nil, &types.NoSuchBucket{}
+			}
+
+			resp, err := provisioner.DriverDeleteBucket(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp).NotTo(BeNil())
+		})
+
+		It("should return DeadlineExceeded error when the context expires during the purge", func() {
+			expiredCtx, cancel := context.WithCancel(ctx)
+			cancel()
+			mockS3.ListObjectVersionsFunc = func(ctx context.Context, input *s3.ListObjectVersionsInput, opts ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error) {
+				return nil, ctx.Err()
+			}
+
+			resp, err := provisioner.DriverDeleteBucket(expiredCtx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.DeadlineExceeded))
+			Expect(err.Error()).To(ContainSubstring("bucket purge interrupted: test-bucket"))
+		})
+
+		It("should return Internal error when objects cannot be deleted", func() {
+			mockS3.ListObjectVersionsFunc = func(ctx context.Context, input *s3.ListObjectVersionsInput, opts ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error) {
+				return &s3.ListObjectVersionsOutput{
+					Versions: []types.ObjectVersion{{Key: aws.String("key"), VersionId: aws.String("v1")}},
+				}, nil
+			}
+			mockS3.DeleteObjectsFunc = func(ctx context.Context, input *s3.DeleteObjectsInput, opts ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverDeleteBucket(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to delete object versions: test-bucket"))
+		})
+	})
+})
+
+var _ = Describe("FetchBucketAccessServiceAccount", func() {
+	var informer *driver.BucketInformer
+
+	BeforeEach(func(specCtx SpecContext) {
+		bucketClientset := bucketfake.NewSimpleClientset(
+			&bucketv1alpha1.BucketAccess{
+				ObjectMeta: metav1.ObjectMeta{Name: "analytics-access", Namespace: "team-a", UID: "1234-abcd"},
+				Spec:       bucketv1alpha1.BucketAccessSpec{ServiceAccountName: "analytics"},
+			},
+			&bucketv1alpha1.BucketAccess{
+				ObjectMeta: metav1.ObjectMeta{Name: "key-access", Namespace: "team-b", UID: "5678-efgh"},
+			},
+		)
+		var err error
+		informer, err = driver.WatchBucketObjects(specCtx, bucketClientset)
+		Expect(err).To(BeNil())
+	})
+
+	It("should return the namespace and service account of the matching bucket access", func() {
+		namespace, serviceAccount, err := driver.FetchBucketAccessServiceAccount(informer, "ba-1234-abcd")
+		Expect(err).To(BeNil())
+		Expect(namespace).To(Equal("team-a"))
+		Expect(serviceAccount).To(Equal("analytics"))
+	})
+
+	It("should return InvalidArgument error when the bucket access has no service account", func() {
+		_, _, err := driver.FetchBucketAccessServiceAccount(informer, "ba-5678-efgh")
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("bucket access team-b/key-access has no service account"))
+	})
+
+	It("should return NotFound error when no bucket access matches the account", func() {
+		_, _, err := driver.FetchBucketAccessServiceAccount(informer, "ba-unknown")
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+
+	It("should return Internal error when bucket accesses are not watched", func() {
+		_, _, err := driver.FetchBucketAccessServiceAccount(nil, "ba-1234-abcd")
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+	})
+})
+
+var _ = Describe("FetchBucketParameters", func() {
+	var (
+		ctx         context.Context
+		provisioner *driver.ProvisionerServer
+	)
+
+	BeforeEach(func(specCtx SpecContext) {
+		ctx = context.TODO()
+		bucketClientset := bucketfake.NewSimpleClientset(
+			&bucketv1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-bucket"},
+				Spec: bucketv1alpha1.BucketSpec{
+					Parameters: map[string]string{
+						"COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME":      "test-secret",
+						"COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAMESPACE": "test-namespace",
+					},
+				},
+			},
+			&bucketv1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: "bucket-claim-uid"},
+				Spec: bucketv1alpha1.BucketSpec{
+					Parameters: map[string]string{"COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME": "templated-secret"},
+				},
+				Status: bucketv1alpha1.BucketStatus{BucketID: "team-a-claim-1a2b3c"},
+			},
+			&bucketv1alpha1.Bucket{
+				ObjectMeta: metav1.ObjectMeta{Name: "static-bucket"},
+				Spec: bucketv1alpha1.BucketSpec{
+					ExistingBucketID: "legacy-bucket",
+					Parameters:       map[string]string{"COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME": "static-secret"},
+				},
+				Status: bucketv1alpha1.BucketStatus{BucketID: "legacy-bucket"},
+			},
+		)
+
+		informer, err := driver.WatchBucketObjects(specCtx, bucketClientset)
+		Expect(err).To(BeNil())
+		provisioner = &driver.ProvisionerServer{
+			Provisioner:     "test-provisioner",
+			BucketClientset: bucketClientset,
+			BucketInformer:  informer,
+		}
+	})
+
+	It("should return the parameters of the bucket object", func() {
+		parameters, err := driver.FetchBucketParameters(ctx, provisioner, "test-bucket")
+		Expect(err).To(BeNil())
+		Expect(parameters).To(HaveKeyWithValue("COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME", "test-secret"))
+		Expect(parameters).To(HaveKeyWithValue("COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAMESPACE", "test-namespace"))
+		Expect(parameters).NotTo(HaveKey("COSI_EXISTING_BUCKET_NAME"))
+	})
+
+	It("should find the bucket object through the bucket ID of its status", func() {
+		parameters, err := driver.FetchBucketParameters(ctx, provisioner, "team-a-claim-1a2b3c")
+		Expect(err).To(BeNil())
+		Expect(parameters).To(HaveKeyWithValue("COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME", "templated-secret"))
+	})
+
+	It("should report the existing bucket of statically provisioned buckets", func() {
+		parameters, err := driver.FetchBucketParameters(ctx, provisioner, "legacy-bucket")
+		Expect(err).To(BeNil())
+		Expect(parameters).To(HaveKeyWithValue("COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME", "static-secret"))
+		Expect(parameters).To(HaveKeyWithValue("COSI_EXISTING_BUCKET_NAME", "legacy-bucket"))
+
+		bucket, err := provisioner.BucketClientset.ObjectstorageV1alpha1().Buckets().Get(ctx, "static-bucket", metav1.GetOptions{})
+		Expect(err).To(BeNil())
+		Expect(bucket.Spec.Parameters).NotTo(HaveKey("COSI_EXISTING_BUCKET_NAME"))
+	})
+
+	It("should return NotFound error when the bucket object does not exist", func() {
+		parameters, err := driver.FetchBucketParameters(ctx, provisioner, "missing-bucket")
+		Expect(err).To(HaveOccurred())
+		Expect(parameters).To(BeNil())
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+		Expect(err.Error()).To(ContainSubstring("bucket object missing-bucket not found"))
+	})
+
+	It("should return NotFound error for bucket IDs that differ from the object name when buckets are not watched", func() {
+		provisioner.BucketInformer = nil
+
+		parameters, err := driver.FetchBucketParameters(ctx, provisioner, "team-a-claim-1a2b3c")
+		Expect(err).To(HaveOccurred())
+		Expect(parameters).To(BeNil())
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+})
+
+var _ = Describe("ProvisionerServer DriverGrantBucketAccess", func() {
+	var (
+		mockIAM                       *MockIAMClient
+		provisioner                   *driver.ProvisionerServer
+		ctx                           context.Context
+		clientset                     *fake.Clientset
+		bucketName                    string
+		userName                      string
+		s3Params                      s3client.S3Params
+		request                       *cosiapi.DriverGrantBucketAccessRequest
+		originalInitializeIAMClient   func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*iamclient.IAMClient, *s3client.S3Params, error)
+		originalFetchBucketParameters func(ctx context.Context, s *driver.ProvisionerServer, bucketName string) (map[string]string, error)
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		mockIAM = &MockIAMClient{}
+		clientset = fake.NewSimpleClientset()
+		provisioner = &driver.ProvisionerServer{
+			Provisioner: "test-provisioner",
+			Clientset:   clientset,
+		}
+		bucketName = "test-bucket"
+		userName = "ba-test-access"
+		s3Params = s3client.S3Params{
+			AccessKey:   "test-access-key",
+			SecretKey:   "test-secret-key",
+			Endpoint:    "https://test-endpoint",
+			IAMEndpoint: "https://test-iam-endpoint",
+			Region:      "us-west-2",
+		}
+		request = &cosiapi.DriverGrantBucketAccessRequest{
+			BucketId:           bucketName,
+			Name:               userName,
+			AuthenticationType: cosiapi.AuthenticationType_Key,
+		}
+
+		originalInitializeIAMClient = driver.InitializeIAMClient
+		originalFetchBucketParameters = driver.FetchBucketParameters
+	})
+
+	AfterEach(func() {
+		driver.InitializeIAMClient = originalInitializeIAMClient
+		driver.FetchBucketParameters = originalFetchBucketParameters
+	})
+
+	JustBeforeEach(func() {
+		driver.FetchBucketParameters = func(ctx context.Context, s *driver.ProvisionerServer, name string) (map[string]string, error) {
+			Expect(name).To(Equal(bucketName))
+			return map[string]string{"COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME": "test-secret"}, nil
+		}
+		driver.InitializeIAMClient = func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*iamclient.IAMClient, *s3client.S3Params, error) {
+			Expect(parameters).To(HaveKeyWithValue("COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME", "test-secret"))
+			return &iamclient.IAMClient{IAMService: mockIAM}, &s3Params, nil
+		}
+	})
+
+	It("should create an IAM user and return its credentials", func() {
+		mockIAM.CreateUserFunc = func(ctx context.Context, input *iam.CreateUserInput, opts ...func(*iam.Options)) (*iam.CreateUserOutput, error) {
+			Expect(input.UserName).To(Equal(&userName))
+			return &iam.CreateUserOutput{}, nil
+		}
+		mockIAM.PutUserPolicyFunc = func(ctx context.Context, input *iam.PutUserPolicyInput, opts ...func(*iam.Options)) (*iam.PutUserPolicyOutput, error) {
+			Expect(input.UserName).To(Equal(&userName))
+			Expect(aws.ToString(input.PolicyDocument)).To(ContainSubstring("arn:aws:s3:::test-bucket"))
+			return &iam.PutUserPolicyOutput{}, nil
+		}
+
+		resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
+		Expect(err).To(BeNil())
+		Expect(resp).NotTo(BeNil())
+		Expect(resp.AccountId).To(Equal(userName))
+		Expect(resp.Credentials).To(HaveKey("s3"))
+		Expect(resp.Credentials["s3"].Secrets).To(Equal(map[string]string{
+			"accessKeyID":     "test-access-key-id",
+			"accessSecretKey": "test-secret-access-key",
+			"endpoint":        "https://test-endpoint",
+			"region":          "us-west-2",
+		}))
+	})
+
+	It("should attach a read-only policy when the access mode is read", func() {
+		request.Parameters = map[string]string{"COSI_ACCESS_MODE": "read"}
+		mockIAM.PutUserPolicyFunc = func(ctx context.Context, input *iam.PutUserPolicyInput, opts ...func(*iam.Options)) (*iam.PutUserPolicyOutput, error) {
+			expectedPolicy, err := driver.GenerateAccessPolicy(driver.AccessModeRead, bucketName)
+			Expect(err).To(BeNil())
+			Expect(aws.ToString(input.PolicyDocument)).To(Equal(expectedPolicy))
+			return &iam.PutUserPolicyOutput{}, nil
+		}
+
+		resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
+		Expect(err).To(BeNil())
+		Expect(resp).NotTo(BeNil())
+	})
+
+	It("should return InvalidArgument error for an invalid access mode", func() {
+		request.Parameters = map[string]string{"COSI_ACCESS_MODE": "execute"}
+
+		resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
+		Expect(resp).To(BeNil())
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("invalid COSI_ACCESS_MODE value: execute"))
+	})
+
+	Context("with IAM authentication", func() {
+		var originalFetchBucketAccessServiceAccount func(informer *driver.BucketInformer, accountName string) (string, string, error)
+
+		BeforeEach(func() {
+			provisioner.IAMAuthentication = true
+			request.AuthenticationType = cosiapi.AuthenticationType_IAM
+			request.Parameters = map[string]string{
+				"COSI_IAM_OIDC_PROVIDER_ARN": "arn:aws:iam::123456789012:oidc-provider/oidc.example.com",
+			}
+			s3Params.STSEndpoint = "https://test-sts-endpoint"
+			originalFetchBucketAccessServiceAccount = driver.FetchBucketAccessServiceAccount
+			driver.FetchBucketAccessServiceAccount = func(informer *driver.BucketInformer, accountName string) (string, string, error) {
+				Expect(accountName).To(Equal(userName))
+				return "team-a", "analytics", nil
+			}
+		})
+
+		AfterEach(func() {
+			driver.FetchBucketAccessServiceAccount = originalFetchBucketAccessServiceAccount
+		})
+
+		It("should create a role trusted by the service account and return its details", func() {
+			mockIAM.CreateUserFunc = func(ctx context.Context, input *iam.CreateUserInput, opts ...func(*iam.Options)) (*iam.CreateUserOutput, error) {
+				Fail("CreateUser should not be called")
+				return nil, nil
+			}
+			mockIAM.CreateAccessKeyFunc = func(ctx context.Context, input *iam.CreateAccessKeyInput, opts ...func(*iam.Options)) (*iam.CreateAccessKeyOutput, error) {
+				Fail("CreateAccessKey should not be called")
+				return nil, nil
+			}
+			mockIAM.CreateRoleFunc = func(ctx context.Context, input *iam.CreateRoleInput, opts ...func(*iam.Options)) (*iam.CreateRoleOutput, error) {
+				Expect(input.RoleName).To(Equal(&userName))
+				Expect(aws.ToString(input.AssumeRolePolicyDocument)).To(ContainSubstring("system:serviceaccount:team-a:analytics"))
+				return &iam.CreateRoleOutput{Role: &iamtypes.Role{Arn: aws.String("arn:aws:iam::123456789012:role/ba-test-access")}}, nil
+			}
+
+			resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
+			Expect(err).To(BeNil())
+			Expect(resp.AccountId).To(Equal(userName))
+			Expect(resp.Credentials["s3"].Secrets).To(Equal(map[string]string{
+				"roleArn":     "arn:aws:iam::123456789012:role/ba-test-access",
+				"stsEndpoint": "https://test-sts-endpoint",
+				"endpoint":    "https://test-endpoint",
+				"region":      "us-west-2",
+			}))
+		})
+
+		It("should return InvalidArgument error when IAM authentication is disabled", func() {
+			provisioner.IAMAuthentication = false
+			mockIAM.CreateRoleFunc = func(ctx context.Context, input *iam.CreateRoleInput, opts ...func(*iam.Options)) (*iam.CreateRoleOutput, error) {
+				Fail("CreateRole should not be called")
+				return nil, nil
+			}
+
+			resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring("IAM authentication is disabled"))
+		})
+
+		It("should return InvalidArgument error when the OIDC provider ARN is missing", func() {
+			request.Parameters = map[string]string{}
+
+			resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring("COSI_IAM_OIDC_PROVIDER_ARN is required for IAM authentication"))
+		})
+
+		It("should return the error when the service account cannot be found", func() {
+			driver.FetchBucketAccessServiceAccount = func(informer *driver.BucketInformer, accountName string) (string, string, error) {
+				return "", "", status.Error(codes.NotFound, "bucket access not found")
+			}
+
+			resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+
+		It("should return Internal error when the role cannot be created", func() {
+			mockIAM.CreateRoleFunc = func(ctx context.Context, input *iam.CreateRoleInput, opts ...func(*iam.Options)) (*iam.CreateRoleOutput, error) {
+				return nil, errors.New("SomeOtherError: Something went wrong")
+			}
+
+			resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
+			Expect(resp).To(BeNil())
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+			Expect(err.Error()).To(ContainSubstring("failed to create bucket access role"))
+		})
+	})
+
+	It("should return InvalidArgument error when the bucket ID is missing", func() {
+		request.BucketId = ""
+
+		resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
+		Expect(resp).To(BeNil())
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("bucket ID and bucket access name are required"))
+	})
+
+	It("should return InvalidArgument error for an unknown authentication type", func() {
+		request.AuthenticationType = cosiapi.AuthenticationType_UnknownAuthenticationType
+
+		resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
+		Expect(resp).To(BeNil())
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("unsupported authentication type"))
+	})
+
+	It("should return the error when bucket parameters cannot be fetched", func() {
+		driver.FetchBucketParameters = func(ctx context.Context, s *driver.ProvisionerServer, name string) (map[string]string, error) {
+			return nil, status.Error(codes.Internal, "failed to get bucket object")
+		}
+
+		resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
+		Expect(resp).To(BeNil())
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+		Expect(err.Error()).To(ContainSubstring("failed to get bucket object"))
+	})
+
+	It("should return Internal error when the IAM client cannot be initialized", func() {
+		driver.InitializeIAMClient = func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*iamclient.IAMClient, *s3client.S3Params, error) {
+			return nil, nil, errors.New("initialization failed")
+		}
+
+		resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
+		Expect(resp).To(BeNil())
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+		Expect(err.Error()).To(ContainSubstring("failed to initialize object storage provider IAM client"))
+	})
+
+	It("should return Internal error when the IAM user cannot be created", func() {
+		mockIAM.CreateUserFunc = func(ctx context.Context, input *iam.CreateUserInput, opts ...func(*iam.Options)) (*iam.CreateUserOutput, error) {
+			return nil, errors.New("SomeOtherError: Something went wrong")
+		}
+
+		resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
+		Expect(resp).To(BeNil())
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+		Expect(err.Error()).To(ContainSubstring("failed to create bucket access"))
+	})
+})
+
+var _ = Describe("ProvisionerServer DriverRevokeBucketAccess", func() {
+	var (
+		mockIAM                       *MockIAMClient
+		provisioner                   *driver.ProvisionerServer
+		ctx                           context.Context
+		clientset                     *fake.Clientset
+		bucketName                    string
+		userName                      string
+		s3Params                      s3client.S3Params
+		request                       *cosiapi.DriverRevokeBucketAccessRequest
+		originalInitializeIAMClient   func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*iamclient.IAMClient, *s3client.S3Params, error)
+		originalFetchBucketParameters func(ctx context.Context, s *driver.ProvisionerServer, bucketName string) (map[string]string, error)
 	)
 
 	BeforeEach(func() {
 		ctx = context.TODO()
-		mockS3 = &MockS3Client{}
+		mockIAM = &MockIAMClient{}
 		clientset = fake.NewSimpleClientset()
 		provisioner = &driver.ProvisionerServer{
 			Provisioner: "test-provisioner",
 			Clientset:   clientset,
 		}
 		bucketName = "test-bucket"
+		userName = "ba-test-access"
 		s3Params = s3client.S3Params{
 			AccessKey: "test-access-key",
 			SecretKey: "test-secret-key",
 			Endpoint:  "https://test-endpoint",
 			Region:    "us-west-2",
 		}
-		request = &cosiapi.DriverCreateBucketRequest{Name: bucketName}
+		request = &cosiapi.DriverRevokeBucketAccessRequest{
+			BucketId:  bucketName,
+			AccountId: userName,
+		}
 
-		// Storing the original method to restore it for other test suits
-		originalInitializeClient = driver.InitializeClient
+		originalInitializeIAMClient = driver.InitializeIAMClient
+		originalFetchBucketParameters = driver.FetchBucketParameters
 	})
 
 	AfterEach(func() {
-		// Restore the original InitializeClient function for other test suites
-		driver.InitializeClient = originalInitializeClient
+		driver.InitializeIAMClient = originalInitializeIAMClient
+		driver.FetchBucketParameters = originalFetchBucketParameters
 	})
 
 	JustBeforeEach(func() {
-		driver.InitializeClient = func(ctx context.Context, clientset kubernetes.Interface, parameters map[string]string) (*s3client.S3Client, *s3client.S3Params, error) {
-			return &s3client.S3Client{S3Service: mockS3}, &s3Params, nil
+		driver.FetchBucketParameters = func(ctx context.Context, s *driver.ProvisionerServer, name string) (map[string]string, error) {
+			Expect(name).To(Equal(bucketName))
+			return map[string]string{"COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME": "test-secret"}, nil
+		}
+		driver.InitializeIAMClient = func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*iamclient.IAMClient, *s3client.S3Params, error) {
+			return &iamclient.IAMClient{IAMService: mockIAM}, &s3Params, nil
 		}
 	})
 
-	It("should successfully create a new bucket", func() {
-		mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
-			Expect(input.Bucket).To(Equal(&bucketName))
-			return &s3.CreateBucketOutput{}, nil
+	It("should delete the IAM user of the bucket access", func() {
+		mockIAM.DeleteUserFunc = func(ctx context.Context, input *iam.DeleteUserInput, opts ...func(*iam.Options)) (*iam.DeleteUserOutput, error) {
+			Expect(input.UserName).To(Equal(&userName))
+			return &iam.DeleteUserOutput{}, nil
 		}
 
-		resp, err := provisioner.DriverCreateBucket(ctx, request)
+		resp, err := provisioner.DriverRevokeBucketAccess(ctx, request)
 		Expect(err).To(BeNil())
 		Expect(resp).NotTo(BeNil())
-		Expect(resp.BucketId).To(Equal(bucketName))
 	})
 
-	It("should return AlreadyExists error if bucket already exists with different parameters", func() {
-		mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
-			return nil, &types.BucketAlreadyExists{}
+	It("should also delete the IAM role of the bucket access", func() {
+		deleted := false
+		mockIAM.DeleteRoleFunc = func(ctx context.Context, input *iam.DeleteRoleInput, opts ...func(*iam.Options)) (*iam.DeleteRoleOutput, error) {
+			Expect(input.RoleName).To(Equal(&userName))
+			deleted = true
+			return &iam.DeleteRoleOutput{}, nil
 		}
 
-		resp, err := provisioner.DriverCreateBucket(ctx, request)
-		Expect(resp).To(BeNil())
-		Expect(err).To(HaveOccurred())
-		Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
-		Expect(err.Error()).To(ContainSubstring("Bucket already exists: test-bucket"))
+		resp, err := provisioner.DriverRevokeBucketAccess(ctx, request)
+		Expect(err).To(BeNil())
+		Expect(resp).NotTo(BeNil())
+		Expect(deleted).To(BeTrue())
 	})
 
-	It("should return success if bucket with same parameters already exists", func() {
-		mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
-			return nil, &types.BucketAlreadyOwnedByYou{}
+	It("should return success if the IAM user does not exist", func() {
+		mockIAM.ListAccessKeysFunc = func(ctx context.Context, input *iam.ListAccessKeysInput, opts ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error) {
+			return nil, &iamtypes.NoSuchEntityException{}
 		}
 
-		resp, err := provisioner.DriverCreateBucket(ctx, request)
+		resp, err := provisioner.DriverRevokeBucketAccess(ctx, request)
 		Expect(err).To(BeNil())
 		Expect(resp).NotTo(BeNil())
-		Expect(resp.BucketId).To(Equal(bucketName))
 	})
 
-	It("should return Internal error for other S3 client errors", func() {
-		mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
-			return nil, errors.New("SomeOtherError: Something went wrong")
-		}
+	It("should return InvalidArgument error when the account ID is missing", func() {
+		request.AccountId = ""
 
-		resp, err := provisioner.DriverCreateBucket(ctx, request)
+		resp, err := provisioner.DriverRevokeBucketAccess(ctx, request)
 		Expect(resp).To(BeNil())
 		Expect(err).To(HaveOccurred())
-		Expect(status.Code(err)).To(Equal(codes.Internal))
-		Expect(err.Error()).To(ContainSubstring("Failed to create bucket"))
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("bucket ID and account ID are required"))
 	})
-})
-
-var _ = Describe("ProvisionerServer Unimplemented Methods", func() {
-	var (
-		provisioner *driver.ProvisionerServer
-		ctx         context.Context
-		clientset   *fake.Clientset
-		bucketName  string
-		accountID   string
-	)
 
-	BeforeEach(func() {
-		ctx = context.TODO()
-		clientset = fake.NewSimpleClientset()
-		provisioner = &driver.ProvisionerServer{
-			Provisioner: "test-provisioner",
-			Clientset:   clientset,
+	It("should return Internal error when the IAM client cannot be initialized", func() {
+		driver.InitializeIAMClient = func(ctx context.Context, s *driver.ProvisionerServer, parameters map[string]string) (*iamclient.IAMClient, *s3client.S3Params, error) {
+			return nil, nil, errors.New("initialization failed")
 		}
-		bucketName = "test-bucket"
-		accountID = "test-account-id"
-	})
 
-	It("DriverDeleteBucket should return Unimplemented error", func() {
-		request := &cosiapi.DriverDeleteBucketRequest{BucketId: bucketName}
-		resp, err := provisioner.DriverDeleteBucket(ctx, request)
+		resp, err := provisioner.DriverRevokeBucketAccess(ctx, request)
 		Expect(resp).To(BeNil())
 		Expect(err).To(HaveOccurred())
-		Expect(status.Code(err)).To(Equal(codes.Unimplemented))
-		Expect(err.Error()).To(ContainSubstring("DriverCreateBucket: not implemented"))
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+		Expect(err.Error()).To(ContainSubstring("failed to initialize object storage provider IAM client"))
 	})
 
-	It("DriverGrantBucketAccess should return Unimplemented error", func() {
-		request := &cosiapi.DriverGrantBucketAccessRequest{
-			BucketId: bucketName,
-			Name:     "test-access",
+	It("should return Internal error when the IAM user cannot be deleted", func() {
+		mockIAM.DeleteUserFunc = func(ctx context.Context, input *iam.DeleteUserInput, opts ...func(*iam.Options)) (*iam.DeleteUserOutput, error) {
+			return nil, errors.New("SomeOtherError: Something went wrong")
 		}
-		resp, err := provisioner.DriverGrantBucketAccess(ctx, request)
-		Expect(resp).To(BeNil())
-		Expect(err).To(HaveOccurred())
-		Expect(status.Code(err)).To(Equal(codes.Unimplemented))
-		Expect(err.Error()).To(ContainSubstring("DriverCreateBucket: not implemented"))
-	})
 
-	It("DriverRevokeBucketAccess should return Unimplemented error", func() {
-		request := &cosiapi.DriverRevokeBucketAccessRequest{AccountId: accountID}
 		resp, err := provisioner.DriverRevokeBucketAccess(ctx, request)
 		Expect(resp).To(BeNil())
 		Expect(err).To(HaveOccurred())
-		Expect(status.Code(err)).To(Equal(codes.Unimplemented))
-		Expect(err.Error()).To(ContainSubstring("DriverCreateBucket: not implemented"))
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+		Expect(err.Error()).To(ContainSubstring("failed to revoke bucket access"))
 	})
 })
 
@@ -261,6 +3589,7 @@ var _ = Describe("initializeObjectStorageClient", func() {
 	var (
 		ctx        context.Context
 		clientset  *fake.Clientset
+		server     *driver.ProvisionerServer
 		parameters map[string]string
 		secret     *corev1.Secret
 	)
@@ -268,6 +3597,7 @@ var _ = Describe("initializeObjectStorageClient", func() {
 	BeforeEach(func() {
 		ctx = context.TODO()
 		clientset = fake.NewSimpleClientset()
+		server = &driver.ProvisionerServer{Clientset: clientset, ClientCache: driver.NewClientCache()}
 		parameters = map[string]string{
 			"COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME":      "test-secret",
 			"COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAMESPACE": "test-namespace",
@@ -291,7 +3621,7 @@ var _ = Describe("initializeObjectStorageClient", func() {
 		_, err := clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
 		Expect(err).To(BeNil())
 
-		s3Client, s3Params, err := driver.InitializeClient(ctx, clientset, parameters)
+		s3Client, s3Params, err := driver.InitializeClient(ctx, server, parameters)
 		Expect(err).To(BeNil())
 		Expect(s3Client).NotTo(BeNil())
 		Expect(s3Params).NotTo(BeNil())
@@ -301,10 +3631,22 @@ var _ = Describe("initializeObjectStorageClient", func() {
 		Expect(s3Params.Region).To(Equal("us-west-2"))
 	})
 
+	It("should successfully initialize IAM client and parameters", func() {
+		secret.Data["COSI_IAM_ENDPOINT"] = []byte("https://test-iam-endpoint")
+		_, err := clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
+		Expect(err).To(BeNil())
+
+		iamClient, s3Params, err := driver.InitializeIAMClient(ctx, server, parameters)
+		Expect(err).To(BeNil())
+		Expect(iamClient).NotTo(BeNil())
+		Expect(s3Params).NotTo(BeNil())
+		Expect(s3Params.IAMEndpoint).To(Equal("https://test-iam-endpoint"))
+	})
+
 	It("should return error when FetchSecretInformation fails", func() {
 		delete(parameters, "COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME")
 
-		s3Client, s3Params, err := driver.InitializeClient(ctx, clientset, parameters)
+		s3Client, s3Params, err := driver.InitializeClient(ctx, server, parameters)
 		Expect(err).To(HaveOccurred())
 		Expect(s3Client).To(BeNil())
 		Expect(s3Params).To(BeNil())
@@ -313,7 +3655,7 @@ var _ = Describe("initializeObjectStorageClient", func() {
 	})
 
 	It("should return error when secret is not found", func() {
-		s3Client, s3Params, err := driver.InitializeClient(ctx, clientset, parameters)
+		s3Client, s3Params, err := driver.InitializeClient(ctx, server, parameters)
 		Expect(err).To(HaveOccurred())
 		Expect(s3Client).To(BeNil())
 		Expect(s3Params).To(BeNil())
@@ -326,12 +3668,244 @@ var _ = Describe("initializeObjectStorageClient", func() {
 		_, err := clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
 		Expect(err).To(BeNil())
 
-		s3Client, s3Params, err := driver.InitializeClient(ctx, clientset, parameters)
+		s3Client, s3Params, err := driver.InitializeClient(ctx, server, parameters)
 		Expect(err).To(HaveOccurred())
 		Expect(s3Client).To(BeNil())
 		Expect(s3Params).To(BeNil())
 		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
-		Expect(err.Error()).To(ContainSubstring("endpoint, accessKeyID, secretKey and region are required"))
+		Expect(err.Error()).To(ContainSubstring("endpoint and region are required"))
+	})
+
+	Context("with a secret that has a resourceVersion", func() {
+		BeforeEach(func() {
+			secret.ResourceVersion = "1"
+		})
+
+		It("should reuse the S3 client while the secret is unchanged", func() {
+			_, err := clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
+			Expect(err).To(BeNil())
+
+			firstClient, firstParams, err := driver.InitializeClient(ctx, server, parameters)
+			Expect(err).To(BeNil())
+			secondClient, secondParams, err := driver.InitializeClient(ctx, server, parameters)
+			Expect(err).To(BeNil())
+			Expect(secondClient).To(BeIdenticalTo(firstClient))
+			Expect(secondParams).To(Equal(firstParams))
+			Expect(secondParams).NotTo(BeIdenticalTo(firstParams))
+		})
+
+		It("should build a single S3 client for concurrent requests", func() {
+			_, err := clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
+			Expect(err).To(BeNil())
+
+			clients := make([]*s3client.S3Client, 8)
+			var wg sync.WaitGroup
+			for i := range clients {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					s3Client, _, err := driver.InitializeClient(ctx, server, parameters)
+					Expect(err).To(BeNil())
+					clients[i] = s3Client
+				}()
+			}
+			wg.Wait()
+
+			for _, s3Client := range clients {
+				Expect(s3Client).To(BeIdenticalTo(clients[0]))
+			}
+		})
+
+		It("should reuse the IAM client while the secret is unchanged", func() {
+			_, err := clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
+			Expect(err).To(BeNil())
+
+			firstClient, _, err := driver.InitializeIAMClient(ctx, server, parameters)
+			Expect(err).To(BeNil())
+			secondClient, _, err := driver.InitializeIAMClient(ctx, server, parameters)
+			Expect(err).To(BeNil())
+			Expect(secondClient).To(BeIdenticalTo(firstClient))
+		})
+
+		It("should build a new client once the secret is updated", func() {
+			_, err := clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
+			Expect(err).To(BeNil())
+
+			firstClient, _, err := driver.InitializeClient(ctx, server, parameters)
+			Expect(err).To(BeNil())
+
+			secret.ResourceVersion = "2"
+			secret.Data["COSI_S3_ACCESS_KEY_ID"] = []byte("rotated-access-key")
+			_, err = clientset.CoreV1().Secrets("test-namespace").Update(ctx, secret, metav1.UpdateOptions{})
+			Expect(err).To(BeNil())
+
+			secondClient, s3Params, err := driver.InitializeClient(ctx, server, parameters)
+			Expect(err).To(BeNil())
+			Expect(secondClient).NotTo(BeIdenticalTo(firstClient))
+			Expect(s3Params.AccessKey).To(Equal("rotated-access-key"))
+		})
+
+		It("should build a new client once the cached one is invalidated", func() {
+			_, err := clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
+			Expect(err).To(BeNil())
+
+			firstClient, _, err := driver.InitializeClient(ctx, server, parameters)
+			Expect(err).To(BeNil())
+
+			server.ClientCache.Invalidate("test-namespace/test-secret")
+
+			secondClient, _, err := driver.InitializeClient(ctx, server, parameters)
+			Expect(err).To(BeNil())
+			Expect(secondClient).NotTo(BeIdenticalTo(firstClient))
+		})
+
+		It("should drop the cached client when the watched secret is deleted", func(specCtx SpecContext) {
+			_, err := clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
+			Expect(err).To(BeNil())
+
+			watchCtx, cancel := context.WithCancel(specCtx)
+			defer cancel()
+			secretInformer, err := driver.WatchObjectStorageProviderSecrets(watchCtx, clientset, "test-namespace", "", server.ClientCache)
+			Expect(err).To(BeNil())
+			server.SecretInformer = secretInformer
+
+			firstClient, _, err := driver.InitializeClient(ctx, server, parameters)
+			Expect(err).To(BeNil())
+
+			// recreating the secret with the same resourceVersion only yields a new client if the deletion was observed
+			Expect(clientset.CoreV1().Secrets("test-namespace").Delete(ctx, "test-secret", metav1.DeleteOptions{})).To(Succeed())
+			_, err = clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
+			Expect(err).To(BeNil())
+
+			Eventually(func() *s3client.S3Client {
+				s3Client, _, err := driver.InitializeClient(ctx, server, parameters)
+				Expect(err).To(BeNil())
+				return s3Client
+			}).WithTimeout(5 * time.Second).ShouldNot(BeIdenticalTo(firstClient))
+		})
+	})
+
+	It("should not cache clients of secrets without a resourceVersion", func() {
+		_, err := clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
+		Expect(err).To(BeNil())
+
+		firstClient, _, err := driver.InitializeClient(ctx, server, parameters)
+		Expect(err).To(BeNil())
+		secondClient, _, err := driver.InitializeClient(ctx, server, parameters)
+		Expect(err).To(BeNil())
+		Expect(secondClient).NotTo(BeIdenticalTo(firstClient))
+	})
+})
+
+var _ = Describe("WatchObjectStorageProviderSecrets", func() {
+	var (
+		ctx        context.Context
+		clientset  *fake.Clientset
+		parameters map[string]string
+		secret     *corev1.Secret
+		server     *driver.ProvisionerServer
+		secretGets int
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		parameters = map[string]string{
+			"COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAME":      "test-secret",
+			"COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAMESPACE": "test-namespace",
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-secret",
+				Namespace: "test-namespace",
+				Labels:    map[string]string{"cosi.scality.com/object-storage-provider": "true"},
+			},
+			Data: map[string][]byte{
+				"COSI_S3_ACCESS_KEY_ID":     []byte("test-access-key"),
+				"COSI_S3_SECRET_ACCESS_KEY": []byte("test-secret-key"),
+				"COSI_S3_ENDPOINT":          []byte("https://test-endpoint"),
+				"COSI_S3_REGION":            []byte("us-west-2"),
+			},
+		}
+
+		secretGets = 0
+		clientset = fake.NewSimpleClientset()
+		// the API server is unavailable for direct secret reads
+		clientset.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			secretGets++
+			return true, nil, errors.New("connection refused")
+		})
+		server = &driver.ProvisionerServer{Clientset: clientset, ClientCache: driver.NewClientCache()}
+	})
+
+	watch := func(watchCtx context.Context) {
+		secretInformer, err := driver.WatchObjectStorageProviderSecrets(watchCtx, clientset, "test-namespace", driver.DefaultSecretLabelSelector, server.ClientCache)
+		Expect(err).To(BeNil())
+		server.SecretInformer = secretInformer
+	}
+
+	It("should read watched secrets from the informer cache", func(specCtx SpecContext) {
+		_, err := clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
+		Expect(err).To(BeNil())
+
+		watchCtx, cancel := context.WithCancel(specCtx)
+		defer cancel()
+		watch(watchCtx)
+
+		s3Client, s3Params, err := driver.InitializeClient(ctx, server, parameters)
+		Expect(err).To(BeNil())
+		Expect(s3Client).NotTo(BeNil())
+		Expect(s3Params.AccessKey).To(Equal("test-access-key"))
+		Expect(secretGets).To(Equal(0))
+	})
+
+	It("should fetch secrets that are not watched from the API server", func(specCtx SpecContext) {
+		secret.Labels = nil
+		_, err := clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
+		Expect(err).To(BeNil())
+
+		watchCtx, cancel := context.WithCancel(specCtx)
+		defer cancel()
+		watch(watchCtx)
+
+		_, _, err = driver.InitializeClient(ctx, server, parameters)
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+		Expect(err.Error()).To(ContainSubstring("failed to get object store user secret"))
+		Expect(secretGets).To(Equal(1))
+	})
+
+	It("should fetch secrets of other namespaces from the API server", func(specCtx SpecContext) {
+		secret.Namespace = "other-namespace"
+		parameters["COSI_OBJECT_STORAGE_PROVIDER_SECRET_NAMESPACE"] = "other-namespace"
+		_, err := clientset.CoreV1().Secrets("other-namespace").Create(ctx, secret, metav1.CreateOptions{})
+		Expect(err).To(BeNil())
+
+		watchCtx, cancel := context.WithCancel(specCtx)
+		defer cancel()
+		watch(watchCtx)
+
+		_, _, err = driver.InitializeClient(ctx, server, parameters)
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+		Expect(secretGets).To(Equal(1))
+	})
+
+	It("should fetch secrets from the API server once the informer is stopped", func(specCtx SpecContext) {
+		_, err := clientset.CoreV1().Secrets("test-namespace").Create(ctx, secret, metav1.CreateOptions{})
+		Expect(err).To(BeNil())
+
+		watchCtx, cancel := context.WithCancel(specCtx)
+		watch(watchCtx)
+		cancel()
+
+		_, _, err = driver.InitializeClient(ctx, server, parameters)
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+		Expect(secretGets).To(Equal(1))
+	})
+
+	It("should return an error for an invalid label selector", func(specCtx SpecContext) {
+		_, err := driver.WatchObjectStorageProviderSecrets(specCtx, clientset, "test-namespace", "cosi.scality.com/object-storage-provider in (", server.ClientCache)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid secret label selector"))
 	})
 })
 
@@ -360,12 +3934,81 @@ var _ = Describe("FetchParameters", func() {
 		Expect(s3Params.TLSCert).To(BeNil())
 	})
 
+	It("should default the IAM endpoint to the S3 endpoint", func() {
+		s3Params, err := driver.FetchParameters(secretData)
+		Expect(err).To(BeNil())
+		Expect(s3Params.IAMEndpoint).To(Equal("https://test-endpoint"))
+	})
+
+	It("should default the STS endpoint to the IAM endpoint", func() {
+		secretData["COSI_IAM_ENDPOINT"] = []byte("https://test-iam-endpoint")
+		s3Params, err := driver.FetchParameters(secretData)
+		Expect(err).To(BeNil())
+		Expect(s3Params.STSEndpoint).To(Equal("https://test-iam-endpoint"))
+	})
+
+	It("should fetch the STS endpoint when provided", func() {
+		secretData["COSI_STS_ENDPOINT"] = []byte("https://test-sts-endpoint")
+		s3Params, err := driver.FetchParameters(secretData)
+		Expect(err).To(BeNil())
+		Expect(s3Params.STSEndpoint).To(Equal("https://test-sts-endpoint"))
+	})
+
+	It("should fetch the IAM endpoint when provided", func() {
+		secretData["COSI_IAM_ENDPOINT"] = []byte("https://test-iam-endpoint")
+		s3Params, err := driver.FetchParameters(secretData)
+		Expect(err).To(BeNil())
+		Expect(s3Params.IAMEndpoint).To(Equal("https://test-iam-endpoint"))
+	})
+
 	It("should successfully fetch S3 parameters with TLS certificate", func() {
-		secretData["COSI_S3_TLS_CERT_SECRET_NAME"] = []byte("test-tls-cert")
+		caCert, _ := generateCertificate()
+		secretData["COSI_S3_TLS_CERT_SECRET_NAME"] = caCert
 		s3Params, err := driver.FetchParameters(secretData)
 		Expect(err).To(BeNil())
 		Expect(s3Params).NotTo(BeNil())
-		Expect(s3Params.TLSCert).To(Equal([]byte("test-tls-cert")))
+		Expect(s3Params.TLSCert).To(Equal(caCert))
+		Expect(s3Params.TLSInsecure).To(BeFalse())
+	})
+
+	It("should return error if the TLS certificate cannot be parsed", func() {
+		secretData["COSI_S3_TLS_CERT_SECRET_NAME"] = []byte("test-tls-cert")
+		s3Params, err := driver.FetchParameters(secretData)
+		Expect(s3Params).To(BeNil())
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("invalid TLS configuration: failed to parse the TLS CA certificate"))
+	})
+
+	It("should disable TLS verification only when insecure TLS is requested", func() {
+		secretData["COSI_S3_TLS_INSECURE"] = []byte("true")
+		s3Params, err := driver.FetchParameters(secretData)
+		Expect(err).To(BeNil())
+		Expect(s3Params.TLSInsecure).To(BeTrue())
+	})
+
+	It("should return error for an invalid insecure TLS value", func() {
+		secretData["COSI_S3_TLS_INSECURE"] = []byte("maybe")
+		_, err := driver.FetchParameters(secretData)
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("invalid COSI_S3_TLS_INSECURE value: maybe"))
+	})
+
+	It("should fetch the TLS client certificate when provided", func() {
+		clientCert, clientKey := generateCertificate()
+		secretData["COSI_S3_TLS_CLIENT_CERT"] = clientCert
+		secretData["COSI_S3_TLS_CLIENT_KEY"] = clientKey
+		s3Params, err := driver.FetchParameters(secretData)
+		Expect(err).To(BeNil())
+		Expect(s3Params.TLSClientCert).To(Equal(clientCert))
+		Expect(s3Params.TLSClientKey).To(Equal(clientKey))
+	})
+
+	It("should return error if the TLS client key is missing", func() {
+		clientCert, _ := generateCertificate()
+		secretData["COSI_S3_TLS_CLIENT_CERT"] = clientCert
+		_, err := driver.FetchParameters(secretData)
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("requires both a certificate and a key"))
 	})
 
 	It("should return error if AccessKey is missing", func() {
@@ -374,7 +4017,7 @@ var _ = Describe("FetchParameters", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(s3Params).To(BeNil())
 		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
-		Expect(err.Error()).To(ContainSubstring("endpoint, accessKeyID, secretKey and region are required"))
+		Expect(err.Error()).To(ContainSubstring("accessKeyID and secretKey are required"))
 	})
 
 	It("should return error if SecretKey is missing", func() {
@@ -383,7 +4026,7 @@ var _ = Describe("FetchParameters", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(s3Params).To(BeNil())
 		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
-		Expect(err.Error()).To(ContainSubstring("endpoint, accessKeyID, secretKey and region are required"))
+		Expect(err.Error()).To(ContainSubstring("accessKeyID and secretKey are required"))
 	})
 
 	It("should return error if Endpoint is missing", func() {
@@ -392,7 +4035,7 @@ var _ = Describe("FetchParameters", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(s3Params).To(BeNil())
 		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
-		Expect(err.Error()).To(ContainSubstring("endpoint, accessKeyID, secretKey and region are required"))
+		Expect(err.Error()).To(ContainSubstring("endpoint and region are required"))
 	})
 
 	It("should return error if Region is missing", func() {
@@ -401,6 +4044,119 @@ var _ = Describe("FetchParameters", func() {
 		Expect(err).To(HaveOccurred())
 		Expect(s3Params).To(BeNil())
 		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
-		Expect(err.Error()).To(ContainSubstring("endpoint, accessKeyID, secretKey and region are required"))
+		Expect(err.Error()).To(ContainSubstring("endpoint and region are required"))
+	})
+
+	It("should fetch the session token when provided", func() {
+		secretData["COSI_S3_SESSION_TOKEN"] = []byte("test-session-token")
+		s3Params, err := driver.FetchParameters(secretData)
+		Expect(err).To(BeNil())
+		Expect(s3Params.CredentialsSource).To(Equal(s3client.CredentialsFromParams))
+		Expect(s3Params.SessionToken).To(Equal("test-session-token"))
+	})
+
+	Context("with credentials mounted in the driver pod", func() {
+		BeforeEach(func() {
+			delete(secretData, "COSI_S3_ACCESS_KEY_ID")
+			delete(secretData, "COSI_S3_SECRET_ACCESS_KEY")
+			secretData["COSI_S3_CREDENTIALS_SOURCE"] = []byte("file")
+			secretData["COSI_S3_CREDENTIALS_NAME"] = []byte("vault-admin")
+		})
+
+		It("should read the credentials from the named directory", func() {
+			s3Params, err := driver.FetchParameters(secretData)
+			Expect(err).To(BeNil())
+			Expect(s3Params.CredentialsSource).To(Equal(s3client.CredentialsFromFiles))
+			Expect(s3Params.CredentialsDir).To(Equal(driver.MountedCredentialsDir + "/vault-admin"))
+			Expect(s3Params.AccessKey).To(BeEmpty())
+		})
+
+		It("should return error if the name is a path", func() {
+			secretData["COSI_S3_CREDENTIALS_NAME"] = []byte("../../../var/run/secrets/kubernetes.io/serviceaccount")
+			s3Params, err := driver.FetchParameters(secretData)
+			Expect(s3Params).To(BeNil())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring("invalid COSI_S3_CREDENTIALS_NAME value"))
+		})
+
+		It("should return error if the name is missing", func() {
+			delete(secretData, "COSI_S3_CREDENTIALS_NAME")
+			_, err := driver.FetchParameters(secretData)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring("invalid COSI_S3_CREDENTIALS_NAME value"))
+		})
+
+		It("should return error if static keys are also set", func() {
+			secretData["COSI_S3_ACCESS_KEY_ID"] = []byte("test-access-key")
+			_, err := driver.FetchParameters(secretData)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring("cannot be set with COSI_S3_CREDENTIALS_SOURCE file"))
+		})
+
+		It("should return error if Endpoint is missing", func() {
+			delete(secretData, "COSI_S3_ENDPOINT")
+			_, err := driver.FetchParameters(secretData)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err.Error()).To(ContainSubstring("endpoint and region are required"))
+		})
+	})
+
+	It("should read the credentials from the driver environment", func() {
+		delete(secretData, "COSI_S3_ACCESS_KEY_ID")
+		delete(secretData, "COSI_S3_SECRET_ACCESS_KEY")
+		secretData["COSI_S3_CREDENTIALS_SOURCE"] = []byte("env")
+		s3Params, err := driver.FetchParameters(secretData)
+		Expect(err).To(BeNil())
+		Expect(s3Params.CredentialsSource).To(Equal(s3client.CredentialsFromEnv))
+	})
+
+	It("should return error for an invalid credentials source", func() {
+		secretData["COSI_S3_CREDENTIALS_SOURCE"] = []byte("vault")
+		_, err := driver.FetchParameters(secretData)
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("invalid COSI_S3_CREDENTIALS_SOURCE value: vault"))
+	})
+
+	It("should fetch the role to assume when provided", func() {
+		secretData["COSI_S3_ROLE_ARN"] = []byte("arn:aws:iam::123456789012:role/cosi-provisioner")
+		secretData["COSI_S3_ROLE_SESSION_NAME"] = []byte("cosi")
+		s3Params, err := driver.FetchParameters(secretData)
+		Expect(err).To(BeNil())
+		Expect(s3Params.RoleARN).To(Equal("arn:aws:iam::123456789012:role/cosi-provisioner"))
+		Expect(s3Params.RoleSessionName).To(Equal("cosi"))
+	})
+
+	It("should return error for an invalid role ARN", func() {
+		secretData["COSI_S3_ROLE_ARN"] = []byte("cosi-provisioner")
+		_, err := driver.FetchParameters(secretData)
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("invalid COSI_S3_ROLE_ARN value: cosi-provisioner"))
+	})
+
+	It("should return error for a role session name without role", func() {
+		secretData["COSI_S3_ROLE_SESSION_NAME"] = []byte("cosi")
+		_, err := driver.FetchParameters(secretData)
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring("COSI_S3_ROLE_SESSION_NAME requires COSI_S3_ROLE_ARN"))
 	})
 })
+
+// generateCertificate returns a self-signed PEM certificate and its PEM private key
+func generateCertificate() ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).To(BeNil())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "cosi-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).To(BeNil())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).To(BeNil())
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// helper method initialized as a variable for testing
+var WatchObjectStorageProviderSecrets = watchObjectStorageProviderSecrets
+
+// DefaultSecretLabelSelector selects the object storage provider secrets watched by the driver
+const DefaultSecretLabelSelector = "cosi.scality.com/object-storage-provider=true"
+
+// SecretInformer serves the watched object storage provider secrets from its cache until it is stopped
+type SecretInformer struct {
+	lister  corelisters.SecretLister
+	stopped <-chan struct{}
+}
+
+// watchObjectStorageProviderSecrets starts an informer on the secrets of a namespace matching the label selector,
+// all its secrets if it is empty. The informer is limited to one namespace so that the driver only needs to list
+// and watch the secrets of its own namespace. Watched secrets are read from the informer cache, which keeps
+// provisioning working while the API server is briefly unavailable, and the clients cached for a secret are
+// dropped as soon as it is updated or deleted, so that credential rotations are picked up right away.
+func watchObjectStorageProviderSecrets(ctx context.Context, clientset kubernetes.Interface, namespace, labelSelector string,
+	clientCache *ClientCache) (*SecretInformer, error) {
+	if _, err := labels.Parse(labelSelector); err != nil {
+		return nil, fmt.Errorf("invalid secret label selector %q: %w", labelSelector, err)
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = labelSelector
+		}))
+	secrets := factory.Core().V1().Secrets()
+	informer := secrets.Informer()
+
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldSecret, ok := oldObj.(*corev1.Secret)
+			if !ok {
+				return
+			}
+			newSecret, ok := newObj.(*corev1.Secret)
+			if !ok || oldSecret.ResourceVersion == newSecret.ResourceVersion {
+				return
+			}
+			clientCache.Invalidate(secretKey(newSecret))
+		},
+		DeleteFunc: func(obj interface{}) {
+			key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+			if err != nil {
+				klog.ErrorS(err, "Failed to get the key of a deleted secret")
+				return
+			}
+			clientCache.Invalidate(key)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	factory.Start(ctx.Done())
+	for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return nil, fmt.Errorf("failed to sync %v informer", informerType)
+		}
+	}
+
+	klog.V(3).InfoS("Watching object storage provider secrets", "namespace", namespace, "labelSelector", labelSelector)
+	return &SecretInformer{lister: secrets.Lister(), stopped: ctx.Done()}, nil
+}
+
+// getObjectStorageProviderSecret returns a secret from the informer cache when it is watched,
+// and from the API server otherwise. The returned secret must not be modified.
+func getObjectStorageProviderSecret(ctx context.Context, clientset kubernetes.Interface, informer *SecretInformer,
+	namespace, name string) (*corev1.Secret, error) {
+	if informer != nil && !isStopped(informer.stopped) {
+		secret, err := informer.lister.Secrets(namespace).Get(name)
+		if err == nil {
+			klog.V(5).InfoS("Secret found in the informer cache", "secretName", name, "namespace", namespace)
+			return secret, nil
+		}
+		if !kerrors.IsNotFound(err) {
+			return nil, err
+		}
+		klog.V(4).InfoS("Secret is not watched, fetching it from the API server", "secretName", name, "namespace", namespace)
+	}
+
+	return clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func isStopped(stopped <-chan struct{}) bool {
+	select {
+	case <-stopped:
+		return true
+	default:
+		return false
+	}
+}
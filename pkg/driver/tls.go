@@ -0,0 +1,51 @@
+/*
+Copyright 2024 Scality, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"strconv"
+
+	s3client "github.com/scality/cosi/pkg/util/s3client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// parseTLSConfig reads the TLS settings of an object storage provider secret into the S3 parameters.
+// Certificates are always verified, against COSI_S3_TLS_CERT_SECRET_NAME or the system roots,
+// unless COSI_S3_TLS_INSECURE is true. COSI_S3_TLS_CLIENT_CERT and COSI_S3_TLS_CLIENT_KEY enable mutual TLS.
+func parseTLSConfig(secretData map[string][]byte, s3Params *s3client.S3Params) error {
+	if value := string(secretData["COSI_S3_TLS_INSECURE"]); value != "" {
+		insecure, err := strconv.ParseBool(value)
+		if err != nil {
+			klog.ErrorS(err, "Invalid TLS insecure value", "value", value)
+			return status.Errorf(codes.InvalidArgument, "invalid COSI_S3_TLS_INSECURE value: %s, expected true or false", value)
+		}
+		s3Params.TLSInsecure = insecure
+	}
+
+	s3Params.TLSCert = secretData["COSI_S3_TLS_CERT_SECRET_NAME"]
+	s3Params.TLSClientCert = secretData["COSI_S3_TLS_CLIENT_CERT"]
+	s3Params.TLSClientKey = secretData["COSI_S3_TLS_CLIENT_KEY"]
+
+	// build the TLS settings once, so that invalid certificates are reported as a configuration error
+	if _, err := s3client.NewTLSConfig(*s3Params); err != nil {
+		klog.ErrorS(err, "Invalid TLS configuration")
+		return status.Errorf(codes.InvalidArgument, "invalid TLS configuration: %v", err)
+	}
+	return nil
+}
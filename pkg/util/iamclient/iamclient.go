@@ -0,0 +1,389 @@
+package iamclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/smithy-go/logging"
+	s3client "github.com/scality/cosi/pkg/util/s3client"
+	"k8s.io/klog/v2"
+)
+
+type IAMAPI interface {
+	CreateUser(ctx context.Context, input *iam.CreateUserInput, opts ...func(*iam.Options)) (*iam.CreateUserOutput, error)
+	PutUserPolicy(ctx context.Context, input *iam.PutUserPolicyInput, opts ...func(*iam.Options)) (*iam.PutUserPolicyOutput, error)
+	ListAccessKeys(ctx context.Context, input *iam.ListAccessKeysInput, opts ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error)
+	CreateAccessKey(ctx context.Context, input *iam.CreateAccessKeyInput, opts ...func(*iam.Options)) (*iam.CreateAccessKeyOutput, error)
+	DeleteAccessKey(ctx context.Context, input *iam.DeleteAccessKeyInput, opts ...func(*iam.Options)) (*iam.DeleteAccessKeyOutput, error)
+	ListUserPolicies(ctx context.Context, input *iam.ListUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListUserPoliciesOutput, error)
+	DeleteUserPolicy(ctx context.Context, input *iam.DeleteUserPolicyInput, opts ...func(*iam.Options)) (*iam.DeleteUserPolicyOutput, error)
+	ListAttachedUserPolicies(ctx context.Context, input *iam.ListAttachedUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListAttachedUserPoliciesOutput, error)
+	DetachUserPolicy(ctx context.Context, input *iam.DetachUserPolicyInput, opts ...func(*iam.Options)) (*iam.DetachUserPolicyOutput, error)
+	DeleteUser(ctx context.Context, input *iam.DeleteUserInput, opts ...func(*iam.Options)) (*iam.DeleteUserOutput, error)
+	CreateRole(ctx context.Context, input *iam.CreateRoleInput, opts ...func(*iam.Options)) (*iam.CreateRoleOutput, error)
+	GetRole(ctx context.Context, input *iam.GetRoleInput, opts ...func(*iam.Options)) (*iam.GetRoleOutput, error)
+	UpdateAssumeRolePolicy(ctx context.Context, input *iam.UpdateAssumeRolePolicyInput, opts ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error)
+	PutRolePolicy(ctx context.Context, input *iam.PutRolePolicyInput, opts ...func(*iam.Options)) (*iam.PutRolePolicyOutput, error)
+	ListRolePolicies(ctx context.Context, input *iam.ListRolePoliciesInput, opts ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error)
+	DeleteRolePolicy(ctx context.Context, input *iam.DeleteRolePolicyInput, opts ...func(*iam.Options)) (*iam.DeleteRolePolicyOutput, error)
+	ListAttachedRolePolicies(ctx context.Context, input *iam.ListAttachedRolePoliciesInput, opts ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error)
+	DetachRolePolicy(ctx context.Context, input *iam.DetachRolePolicyInput, opts ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error)
+	DeleteRole(ctx context.Context, input *iam.DeleteRoleInput, opts ...func(*iam.Options)) (*iam.DeleteRoleOutput, error)
+}
+
+const defaultRegion = "us-east-1"
+
+type IAMClient struct {
+	IAMService IAMAPI
+	httpClient *http.Client
+}
+
+// InitIAMClient creates an IAM client for the Vault IAM endpoint of the object storage provider.
+// The IAM endpoint falls back to the S3 endpoint when it is not set.
+func InitIAMClient(params s3client.S3Params) (*IAMClient, error) {
+	var logger logging.Logger
+	if params.Debug {
+		logger = logging.NewStandardLogger(os.Stdout)
+	} else {
+		logger = nil
+	}
+
+	endpoint := params.IAMEndpoint
+	if endpoint == "" {
+		endpoint = params.Endpoint
+	}
+
+	httpClient, err := s3client.NewHTTPClient(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure TLS: %w", err)
+	}
+
+	credentialsProvider, err := s3client.NewCredentialsProvider(params, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	region := params.Region
+	if region == "" {
+		region = defaultRegion
+	}
+
+	ctx := context.Background()
+
+	awsCfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credentialsProvider),
+		config.WithHTTPClient(httpClient),
+		config.WithLogger(logger),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	iamClient := iam.NewFromConfig(awsCfg, func(o *iam.Options) {
+		o.BaseEndpoint = aws.String(endpoint)
+	})
+
+	return &IAMClient{
+		IAMService: iamClient,
+		httpClient: httpClient,
+	}, nil
+}
+
+// CloseIdleConnections closes the idle connections of the client, once it is no longer used.
+func (client *IAMClient) CloseIdleConnections() {
+	if client.httpClient != nil {
+		client.httpClient.CloseIdleConnections()
+	}
+}
+
+// CreateBucketAccess creates an IAM user with the given inline policy for a bucket and returns a new access key for it.
+// Calling it again for the same user replaces the policy and the previous access keys,
+// as their secret part cannot be retrieved once the creation response is lost.
+func (client *IAMClient) CreateBucketAccess(ctx context.Context, userName, bucketName, policyDocument string) (*types.AccessKey, error) {
+	if err := client.createUser(ctx, userName); err != nil {
+		return nil, err
+	}
+
+	_, err := client.IAMService.PutUserPolicy(ctx, &iam.PutUserPolicyInput{
+		UserName:       &userName,
+		PolicyName:     &bucketName,
+		PolicyDocument: aws.String(policyDocument),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach policy to IAM user %s: %w", userName, err)
+	}
+
+	if err := client.deleteAccessKeys(ctx, userName); err != nil {
+		return nil, err
+	}
+
+	output, err := client.IAMService.CreateAccessKey(ctx, &iam.CreateAccessKeyInput{
+		UserName: &userName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create access key for IAM user %s: %w", userName, err)
+	}
+
+	klog.InfoS("Bucket access creation operation succeeded", "userName", userName, "bucketName", bucketName)
+	return output.AccessKey, nil
+}
+
+func (client *IAMClient) createUser(ctx context.Context, userName string) error {
+	_, err := client.IAMService.CreateUser(ctx, &iam.CreateUserInput{
+		UserName: &userName,
+	})
+	if err != nil {
+		var alreadyExists *types.EntityAlreadyExistsException
+		if errors.As(err, &alreadyExists) {
+			klog.V(3).InfoS("IAM user already exists", "userName", userName)
+			return nil
+		}
+		return fmt.Errorf("failed to create IAM user %s: %w", userName, err)
+	}
+
+	klog.V(3).InfoS("IAM user created", "userName", userName)
+	return nil
+}
+
+func (client *IAMClient) deleteAccessKeys(ctx context.Context, userName string) error {
+	paginator := iam.NewListAccessKeysPaginator(client.IAMService, &iam.ListAccessKeysInput{
+		UserName: &userName,
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list access keys for IAM user %s: %w", userName, err)
+		}
+
+		for _, key := range page.AccessKeyMetadata {
+			_, err := client.IAMService.DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{
+				UserName:    &userName,
+				AccessKeyId: key.AccessKeyId,
+			})
+			if err != nil {
+				if isNoSuchEntity(err) {
+					continue
+				}
+				return fmt.Errorf("failed to delete access key %s for IAM user %s: %w", aws.ToString(key.AccessKeyId), userName, err)
+			}
+			klog.V(4).InfoS("IAM access key deleted", "userName", userName, "accessKeyId", aws.ToString(key.AccessKeyId))
+		}
+	}
+	return nil
+}
+
+// RevokeBucketAccess deletes the access keys and inline policies of an IAM user, detaches its managed
+// policies and deletes the user. Managed policies are only detached as they may be shared with other users.
+// Entities that no longer exist are skipped, so the revocation can be retried safely.
+func (client *IAMClient) RevokeBucketAccess(ctx context.Context, userName string) error {
+	if err := client.deleteAccessKeys(ctx, userName); err != nil {
+		if isNoSuchEntity(err) {
+			klog.V(3).InfoS("IAM user does not exist, nothing to revoke", "userName", userName)
+			return nil
+		}
+		return err
+	}
+
+	if err := client.deleteInlinePolicies(ctx, userName); err != nil {
+		return err
+	}
+
+	if err := client.detachManagedPolicies(ctx, userName); err != nil {
+		return err
+	}
+
+	_, err := client.IAMService.DeleteUser(ctx, &iam.DeleteUserInput{
+		UserName: &userName,
+	})
+	if err != nil && !isNoSuchEntity(err) {
+		return fmt.Errorf("failed to delete IAM user %s: %w", userName, err)
+	}
+
+	klog.InfoS("Bucket access revocation operation succeeded", "userName", userName)
+	return nil
+}
+
+func (client *IAMClient) deleteInlinePolicies(ctx context.Context, userName string) error {
+	paginator := iam.NewListUserPoliciesPaginator(client.IAMService, &iam.ListUserPoliciesInput{
+		UserName: &userName,
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			if isNoSuchEntity(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to list inline policies for IAM user %s: %w", userName, err)
+		}
+
+		for _, policyName := range page.PolicyNames {
+			_, err := client.IAMService.DeleteUserPolicy(ctx, &iam.DeleteUserPolicyInput{
+				UserName:   &userName,
+				PolicyName: aws.String(policyName),
+			})
+			if err != nil && !isNoSuchEntity(err) {
+				return fmt.Errorf("failed to delete inline policy %s for IAM user %s: %w", policyName, userName, err)
+			}
+			klog.V(4).InfoS("IAM inline policy deleted", "userName", userName, "policyName", policyName)
+		}
+	}
+	return nil
+}
+
+func (client *IAMClient) detachManagedPolicies(ctx context.Context, userName string) error {
+	paginator := iam.NewListAttachedUserPoliciesPaginator(client.IAMService, &iam.ListAttachedUserPoliciesInput{
+		UserName: &userName,
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			if isNoSuchEntity(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to list attached policies for IAM user %s: %w", userName, err)
+		}
+
+		for _, policy := range page.AttachedPolicies {
+			_, err := client.IAMService.DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{
+				UserName:  &userName,
+				PolicyArn: policy.PolicyArn,
+			})
+			if err != nil && !isNoSuchEntity(err) {
+				return fmt.Errorf("failed to detach policy %s from IAM user %s: %w", aws.ToString(policy.PolicyArn), userName, err)
+			}
+			klog.V(4).InfoS("IAM managed policy detached", "userName", userName, "policyArn", aws.ToString(policy.PolicyArn))
+		}
+	}
+	return nil
+}
+
+// CreateBucketAccessRole creates an IAM role that can be assumed according to the given trust policy,
+// with the given inline policy for a bucket. An existing role gets its trust and inline policies updated.
+func (client *IAMClient) CreateBucketAccessRole(ctx context.Context, roleName, bucketName, trustPolicy, policyDocument string) (*types.Role, error) {
+	role, err := client.createRole(ctx, roleName, trustPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.IAMService.PutRolePolicy(ctx, &iam.PutRolePolicyInput{
+		RoleName:       &roleName,
+		PolicyName:     &bucketName,
+		PolicyDocument: aws.String(policyDocument),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach policy to IAM role %s: %w", roleName, err)
+	}
+
+	klog.InfoS("Bucket access role creation operation succeeded", "roleName", roleName, "bucketName", bucketName)
+	return role, nil
+}
+
+func (client *IAMClient) createRole(ctx context.Context, roleName, trustPolicy string) (*types.Role, error) {
+	output, err := client.IAMService.CreateRole(ctx, &iam.CreateRoleInput{
+		RoleName:                 &roleName,
+		AssumeRolePolicyDocument: aws.String(trustPolicy),
+	})
+	if err == nil {
+		klog.V(3).InfoS("IAM role created", "roleName", roleName)
+		return output.Role, nil
+	}
+
+	var alreadyExists *types.EntityAlreadyExistsException
+	if !errors.As(err, &alreadyExists) {
+		return nil, fmt.Errorf("failed to create IAM role %s: %w", roleName, err)
+	}
+
+	klog.V(3).InfoS("IAM role already exists, updating its trust policy", "roleName", roleName)
+	_, err = client.IAMService.UpdateAssumeRolePolicy(ctx, &iam.UpdateAssumeRolePolicyInput{
+		RoleName:       &roleName,
+		PolicyDocument: aws.String(trustPolicy),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update trust policy of IAM role %s: %w", roleName, err)
+	}
+
+	existing, err := client.IAMService.GetRole(ctx, &iam.GetRoleInput{
+		RoleName: &roleName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get IAM role %s: %w", roleName, err)
+	}
+	return existing.Role, nil
+}
+
+// RevokeBucketAccessRole deletes the inline policies of an IAM role, detaches its managed policies
+// and deletes the role. Entities that no longer exist are skipped, so the revocation can be retried safely.
+func (client *IAMClient) RevokeBucketAccessRole(ctx context.Context, roleName string) error {
+	rolePolicies := iam.NewListRolePoliciesPaginator(client.IAMService, &iam.ListRolePoliciesInput{
+		RoleName: &roleName,
+	})
+	for rolePolicies.HasMorePages() {
+		page, err := rolePolicies.NextPage(ctx)
+		if err != nil {
+			if isNoSuchEntity(err) {
+				klog.V(3).InfoS("IAM role does not exist, nothing to revoke", "roleName", roleName)
+				return nil
+			}
+			return fmt.Errorf("failed to list inline policies for IAM role %s: %w", roleName, err)
+		}
+
+		for _, policyName := range page.PolicyNames {
+			_, err := client.IAMService.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{
+				RoleName:   &roleName,
+				PolicyName: aws.String(policyName),
+			})
+			if err != nil && !isNoSuchEntity(err) {
+				return fmt.Errorf("failed to delete inline policy %s for IAM role %s: %w", policyName, roleName, err)
+			}
+		}
+	}
+
+	attachedPolicies := iam.NewListAttachedRolePoliciesPaginator(client.IAMService, &iam.ListAttachedRolePoliciesInput{
+		RoleName: &roleName,
+	})
+	for attachedPolicies.HasMorePages() {
+		page, err := attachedPolicies.NextPage(ctx)
+		if err != nil {
+			if isNoSuchEntity(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to list attached policies for IAM role %s: %w", roleName, err)
+		}
+
+		for _, policy := range page.AttachedPolicies {
+			_, err := client.IAMService.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+				RoleName:  &roleName,
+				PolicyArn: policy.PolicyArn,
+			})
+			if err != nil && !isNoSuchEntity(err) {
+				return fmt.Errorf("failed to detach policy %s from IAM role %s: %w", aws.ToString(policy.PolicyArn), roleName, err)
+			}
+		}
+	}
+
+	_, err := client.IAMService.DeleteRole(ctx, &iam.DeleteRoleInput{
+		RoleName: &roleName,
+	})
+	if err != nil && !isNoSuchEntity(err) {
+		return fmt.Errorf("failed to delete IAM role %s: %w", roleName, err)
+	}
+
+	klog.InfoS("Bucket access role revocation operation succeeded", "roleName", roleName)
+	return nil
+}
+
+func isNoSuchEntity(err error) bool {
+	var noSuchEntity *types.NoSuchEntityException
+	return errors.As(err, &noSuchEntity)
+}
@@ -0,0 +1,578 @@
+package iamclient_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/scality/cosi/pkg/util/iamclient"
+	"github.com/scality/cosi/pkg/util/s3client"
+)
+
+// MockIAMClient implements the IAMAPI interface for testing
+type MockIAMClient struct {
+	CreateUserFunc               func(ctx context.Context, input *iam.CreateUserInput, opts ...func(*iam.Options)) (*iam.CreateUserOutput, error)
+	PutUserPolicyFunc            func(ctx context.Context, input *iam.PutUserPolicyInput, opts ...func(*iam.Options)) (*iam.PutUserPolicyOutput, error)
+	ListAccessKeysFunc           func(ctx context.Context, input *iam.ListAccessKeysInput, opts ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error)
+	CreateAccessKeyFunc          func(ctx context.Context, input *iam.CreateAccessKeyInput, opts ...func(*iam.Options)) (*iam.CreateAccessKeyOutput, error)
+	DeleteAccessKeyFunc          func(ctx context.Context, input *iam.DeleteAccessKeyInput, opts ...func(*iam.Options)) (*iam.DeleteAccessKeyOutput, error)
+	ListUserPoliciesFunc         func(ctx context.Context, input *iam.ListUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListUserPoliciesOutput, error)
+	DeleteUserPolicyFunc         func(ctx context.Context, input *iam.DeleteUserPolicyInput, opts ...func(*iam.Options)) (*iam.DeleteUserPolicyOutput, error)
+	ListAttachedUserPoliciesFunc func(ctx context.Context, input *iam.ListAttachedUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListAttachedUserPoliciesOutput, error)
+	DetachUserPolicyFunc         func(ctx context.Context, input *iam.DetachUserPolicyInput, opts ...func(*iam.Options)) (*iam.DetachUserPolicyOutput, error)
+	DeleteUserFunc               func(ctx context.Context, input *iam.DeleteUserInput, opts ...func(*iam.Options)) (*iam.DeleteUserOutput, error)
+	CreateRoleFunc               func(ctx context.Context, input *iam.CreateRoleInput, opts ...func(*iam.Options)) (*iam.CreateRoleOutput, error)
+	GetRoleFunc                  func(ctx context.Context, input *iam.GetRoleInput, opts ...func(*iam.Options)) (*iam.GetRoleOutput, error)
+	UpdateAssumeRolePolicyFunc   func(ctx context.Context, input *iam.UpdateAssumeRolePolicyInput, opts ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error)
+	PutRolePolicyFunc            func(ctx context.Context, input *iam.PutRolePolicyInput, opts ...func(*iam.Options)) (*iam.PutRolePolicyOutput, error)
+	ListRolePoliciesFunc         func(ctx context.Context, input *iam.ListRolePoliciesInput, opts ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error)
+	DeleteRolePolicyFunc         func(ctx context.Context, input *iam.DeleteRolePolicyInput, opts ...func(*iam.Options)) (*iam.DeleteRolePolicyOutput, error)
+	ListAttachedRolePoliciesFunc func(ctx context.Context, input *iam.ListAttachedRolePoliciesInput, opts ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error)
+	DetachRolePolicyFunc         func(ctx context.Context, input *iam.DetachRolePolicyInput, opts ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error)
+	DeleteRoleFunc               func(ctx context.Context, input *iam.DeleteRoleInput, opts ...func(*iam.Options)) (*iam.DeleteRoleOutput, error)
+}
+
+func (m *MockIAMClient) CreateUser(ctx context.Context, input *iam.CreateUserInput, opts ...func(*iam.Options)) (*iam.CreateUserOutput, error) {
+	if m.CreateUserFunc != nil {
+		return m.CreateUserFunc(ctx, input, opts...)
+	}
+	return &iam.CreateUserOutput{}, nil
+}
+
+func (m *MockIAMClient) PutUserPolicy(ctx context.Context, input *iam.PutUserPolicyInput, opts ...func(*iam.Options)) (*iam.PutUserPolicyOutput, error) {
+	if m.PutUserPolicyFunc != nil {
+		return m.PutUserPolicyFunc(ctx, input, opts...)
+	}
+	return &iam.PutUserPolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) ListAccessKeys(ctx context.Context, input *iam.ListAccessKeysInput, opts ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error) {
+	if m.ListAccessKeysFunc != nil {
+		return m.ListAccessKeysFunc(ctx, input, opts...)
+	}
+	return &iam.ListAccessKeysOutput{}, nil
+}
+
+func (m *MockIAMClient) CreateAccessKey(ctx context.Context, input *iam.CreateAccessKeyInput, opts ...func(*iam.Options)) (*iam.CreateAccessKeyOutput, error) {
+	if m.CreateAccessKeyFunc != nil {
+		return m.CreateAccessKeyFunc(ctx, input, opts...)
+	}
+	return &iam.CreateAccessKeyOutput{
+		AccessKey: &types.AccessKey{
+			AccessKeyId:     aws.String("test-access-key-id"),
+			SecretAccessKey: aws.String("test-secret-access-key"),
+			UserName:        input.UserName,
+		},
+	}, nil
+}
+
+func (m *MockIAMClient) DeleteAccessKey(ctx context.Context, input *iam.DeleteAccessKeyInput, opts ...func(*iam.Options)) (*iam.DeleteAccessKeyOutput, error) {
+	if m.DeleteAccessKeyFunc != nil {
+		return m.DeleteAccessKeyFunc(ctx, input, opts...)
+	}
+	return &iam.DeleteAccessKeyOutput{}, nil
+}
+
+func (m *MockIAMClient) ListUserPolicies(ctx context.Context, input *iam.ListUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListUserPoliciesOutput, error) {
+	if m.ListUserPoliciesFunc != nil {
+		return m.ListUserPoliciesFunc(ctx, input, opts...)
+	}
+	return &iam.ListUserPoliciesOutput{}, nil
+}
+
+func (m *MockIAMClient) DeleteUserPolicy(ctx context.Context, input *iam.DeleteUserPolicyInput, opts ...func(*iam.Options)) (*iam.DeleteUserPolicyOutput, error) {
+	if m.DeleteUserPolicyFunc != nil {
+		return m.DeleteUserPolicyFunc(ctx, input, opts...)
+	}
+	return &iam.DeleteUserPolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) ListAttachedUserPolicies(ctx context.Context, input *iam.ListAttachedUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListAttachedUserPoliciesOutput, error) {
+	if m.ListAttachedUserPoliciesFunc != nil {
+		return m.ListAttachedUserPoliciesFunc(ctx, input, opts...)
+	}
+	return &iam.ListAttachedUserPoliciesOutput{}, nil
+}
+
+func (m *MockIAMClient) DetachUserPolicy(ctx context.Context, input *iam.DetachUserPolicyInput, opts ...func(*iam.Options)) (*iam.DetachUserPolicyOutput, error) {
+	if m.DetachUserPolicyFunc != nil {
+		return m.DetachUserPolicyFunc(ctx, input, opts...)
+	}
+	return &iam.DetachUserPolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) DeleteUser(ctx context.Context, input *iam.DeleteUserInput, opts ...func(*iam.Options)) (*iam.DeleteUserOutput, error) {
+	if m.DeleteUserFunc != nil {
+		return m.DeleteUserFunc(ctx, input, opts...)
+	}
+	return &iam.DeleteUserOutput{}, nil
+}
+
+func (m *MockIAMClient) CreateRole(ctx context.Context, input *iam.CreateRoleInput, opts ...func(*iam.Options)) (*iam.CreateRoleOutput, error) {
+	if m.CreateRoleFunc != nil {
+		return m.CreateRoleFunc(ctx, input, opts...)
+	}
+	return &iam.CreateRoleOutput{
+		Role: &types.Role{
+			RoleName: input.RoleName,
+			Arn:      aws.String("arn:aws:iam::123456789012:role/" + aws.ToString(input.RoleName)),
+		},
+	}, nil
+}
+
+func (m *MockIAMClient) GetRole(ctx context.Context, input *iam.GetRoleInput, opts ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+	if m.GetRoleFunc != nil {
+		return m.GetRoleFunc(ctx, input, opts...)
+	}
+	return &iam.GetRoleOutput{
+		Role: &types.Role{
+			RoleName: input.RoleName,
+			Arn:      aws.String("arn:aws:iam::123456789012:role/" + aws.ToString(input.RoleName)),
+		},
+	}, nil
+}
+
+func (m *MockIAMClient) UpdateAssumeRolePolicy(ctx context.Context, input *iam.UpdateAssumeRolePolicyInput, opts ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error) {
+	if m.UpdateAssumeRolePolicyFunc != nil {
+		return m.UpdateAssumeRolePolicyFunc(ctx, input, opts...)
+	}
+	return &iam.UpdateAssumeRolePolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) PutRolePolicy(ctx context.Context, input *iam.PutRolePolicyInput, opts ...func(*iam.Options)) (*iam.PutRolePolicyOutput, error) {
+	if m.PutRolePolicyFunc != nil {
+		return m.PutRolePolicyFunc(ctx, input, opts...)
+	}
+	return &iam.PutRolePolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) ListRolePolicies(ctx context.Context, input *iam.ListRolePoliciesInput, opts ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error) {
+	if m.ListRolePoliciesFunc != nil {
+		return m.ListRolePoliciesFunc(ctx, input, opts...)
+	}
+	return &iam.ListRolePoliciesOutput{}, nil
+}
+
+func (m *MockIAMClient) DeleteRolePolicy(ctx context.Context, input *iam.DeleteRolePolicyInput, opts ...func(*iam.Options)) (*iam.DeleteRolePolicyOutput, error) {
+	if m.DeleteRolePolicyFunc != nil {
+		return m.DeleteRolePolicyFunc(ctx, input, opts...)
+	}
+	return &iam.DeleteRolePolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) ListAttachedRolePolicies(ctx context.Context, input *iam.ListAttachedRolePoliciesInput, opts ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error) {
+	if m.ListAttachedRolePoliciesFunc != nil {
+		return m.ListAttachedRolePoliciesFunc(ctx, input, opts...)
+	}
+	return &iam.ListAttachedRolePoliciesOutput{}, nil
+}
+
+func (m *MockIAMClient) DetachRolePolicy(ctx context.Context, input *iam.DetachRolePolicyInput, opts ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error) {
+	if m.DetachRolePolicyFunc != nil {
+		return m.DetachRolePolicyFunc(ctx, input, opts...)
+	}
+	return &iam.DetachRolePolicyOutput{}, nil
+}
+
+func (m *MockIAMClient) DeleteRole(ctx context.Context, input *iam.DeleteRoleInput, opts ...func(*iam.Options)) (*iam.DeleteRoleOutput, error) {
+	if m.DeleteRoleFunc != nil {
+		return m.DeleteRoleFunc(ctx, input, opts...)
+	}
+	return &iam.DeleteRoleOutput{}, nil
+}
+
+const testPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::test-bucket/*"]}]}`
+
+func TestIAMClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "IAMClient Suite")
+}
+
+var _ = Describe("IAMClient", func() {
+
+	var params s3client.S3Params
+
+	BeforeEach(func() {
+		params = s3client.S3Params{
+			AccessKey:   "test-access-key",
+			SecretKey:   "test-secret-key",
+			Endpoint:    "https://s3.mock.endpoint",
+			IAMEndpoint: "https://iam.mock.endpoint",
+			Region:      "us-west-2",
+			TLSCert:     nil,
+			Debug:       false,
+		}
+	})
+
+	Describe("InitIAMClient", func() {
+		It("should initialize the IAM client without error", func() {
+			client, err := iamclient.InitIAMClient(params)
+			Expect(err).To(BeNil())
+			Expect(client).NotTo(BeNil())
+			opts := client.IAMService.(*iam.Client).Options()
+			Expect(opts.BaseEndpoint).To(Equal(aws.String("https://iam.mock.endpoint")))
+			Expect(opts.Region).To(Equal("us-west-2"))
+		})
+
+		It("should fall back to the S3 endpoint when no IAM endpoint is provided", func() {
+			params.IAMEndpoint = ""
+			client, err := iamclient.InitIAMClient(params)
+			Expect(err).To(BeNil())
+			opts := client.IAMService.(*iam.Client).Options()
+			Expect(opts.BaseEndpoint).To(Equal(aws.String("https://s3.mock.endpoint")))
+		})
+
+		It("should use the default region when none is provided", func() {
+			params.Region = ""
+			client, err := iamclient.InitIAMClient(params)
+			Expect(err).To(BeNil())
+			opts := client.IAMService.(*iam.Client).Options()
+			Expect(opts.Region).To(Equal("us-east-1"))
+		})
+
+		It("should fail if credentials are missing", func() {
+			params.AccessKey = ""
+			params.SecretKey = ""
+			client, err := iamclient.InitIAMClient(params)
+			Expect(err).NotTo(BeNil())
+			Expect(client).To(BeNil())
+		})
+	})
+
+	Describe("CreateBucketAccess", func() {
+		var (
+			mockIAM *MockIAMClient
+			client  *iamclient.IAMClient
+		)
+
+		BeforeEach(func() {
+			mockIAM = &MockIAMClient{}
+			client = &iamclient.IAMClient{IAMService: mockIAM}
+		})
+
+		It("should create a user, attach the bucket policy and return an access key", func(ctx SpecContext) {
+			mockIAM.CreateUserFunc = func(ctx context.Context, input *iam.CreateUserInput, opts ...func(*iam.Options)) (*iam.CreateUserOutput, error) {
+				Expect(input.UserName).To(Equal(aws.String("test-user")))
+				return &iam.CreateUserOutput{}, nil
+			}
+			mockIAM.PutUserPolicyFunc = func(ctx context.Context, input *iam.PutUserPolicyInput, opts ...func(*iam.Options)) (*iam.PutUserPolicyOutput, error) {
+				Expect(input.UserName).To(Equal(aws.String("test-user")))
+				Expect(input.PolicyName).To(Equal(aws.String("test-bucket")))
+				Expect(input.PolicyDocument).To(Equal(aws.String(testPolicy)))
+				return &iam.PutUserPolicyOutput{}, nil
+			}
+
+			accessKey, err := client.CreateBucketAccess(ctx, "test-user", "test-bucket", testPolicy)
+			Expect(err).To(BeNil())
+			Expect(accessKey.AccessKeyId).To(Equal(aws.String("test-access-key-id")))
+			Expect(accessKey.SecretAccessKey).To(Equal(aws.String("test-secret-access-key")))
+		})
+
+		It("should reuse an existing user and replace its access keys", func(ctx SpecContext) {
+			mockIAM.CreateUserFunc = func(ctx context.Context, input *iam.CreateUserInput, opts ...func(*iam.Options)) (*iam.CreateUserOutput, error) {
+				return nil, &types.EntityAlreadyExistsException{}
+			}
+			mockIAM.ListAccessKeysFunc = func(ctx context.Context, input *iam.ListAccessKeysInput, opts ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error) {
+				return &iam.ListAccessKeysOutput{
+					AccessKeyMetadata: []types.AccessKeyMetadata{{AccessKeyId: aws.String("old-key")}},
+				}, nil
+			}
+			var deletedKeys []string
+			mockIAM.DeleteAccessKeyFunc = func(ctx context.Context, input *iam.DeleteAccessKeyInput, opts ...func(*iam.Options)) (*iam.DeleteAccessKeyOutput, error) {
+				deletedKeys = append(deletedKeys, aws.ToString(input.AccessKeyId))
+				return &iam.DeleteAccessKeyOutput{}, nil
+			}
+
+			accessKey, err := client.CreateBucketAccess(ctx, "test-user", "test-bucket", testPolicy)
+			Expect(err).To(BeNil())
+			Expect(accessKey).NotTo(BeNil())
+			Expect(deletedKeys).To(Equal([]string{"old-key"}))
+		})
+
+		It("should return an error when the user cannot be created", func(ctx SpecContext) {
+			mockIAM.CreateUserFunc = func(ctx context.Context, input *iam.CreateUserInput, opts ...func(*iam.Options)) (*iam.CreateUserOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			accessKey, err := client.CreateBucketAccess(ctx, "test-user", "test-bucket", testPolicy)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("failed to create IAM user test-user"))
+			Expect(accessKey).To(BeNil())
+		})
+
+		It("should return an error when the policy cannot be attached", func(ctx SpecContext) {
+			mockIAM.PutUserPolicyFunc = func(ctx context.Context, input *iam.PutUserPolicyInput, opts ...func(*iam.Options)) (*iam.PutUserPolicyOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			accessKey, err := client.CreateBucketAccess(ctx, "test-user", "test-bucket", testPolicy)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("failed to attach policy to IAM user test-user"))
+			Expect(accessKey).To(BeNil())
+		})
+
+		It("should return an error when the access key cannot be created", func(ctx SpecContext) {
+			mockIAM.CreateAccessKeyFunc = func(ctx context.Context, input *iam.CreateAccessKeyInput, opts ...func(*iam.Options)) (*iam.CreateAccessKeyOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			accessKey, err := client.CreateBucketAccess(ctx, "test-user", "test-bucket", testPolicy)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("failed to create access key for IAM user test-user"))
+			Expect(accessKey).To(BeNil())
+		})
+	})
+
+	Describe("RevokeBucketAccess", func() {
+		var (
+			mockIAM *MockIAMClient
+			client  *iamclient.IAMClient
+		)
+
+		BeforeEach(func() {
+			mockIAM = &MockIAMClient{}
+			client = &iamclient.IAMClient{IAMService: mockIAM}
+		})
+
+		It("should delete keys and inline policies, detach managed policies and delete the user", func(ctx SpecContext) {
+			var calls []string
+			mockIAM.ListAccessKeysFunc = func(ctx context.Context, input *iam.ListAccessKeysInput, opts ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error) {
+				return &iam.ListAccessKeysOutput{
+					AccessKeyMetadata: []types.AccessKeyMetadata{{AccessKeyId: aws.String("key-1")}, {AccessKeyId: aws.String("key-2")}},
+				}, nil
+			}
+			mockIAM.DeleteAccessKeyFunc = func(ctx context.Context, input *iam.DeleteAccessKeyInput, opts ...func(*iam.Options)) (*iam.DeleteAccessKeyOutput, error) {
+				calls = append(calls, "DeleteAccessKey "+aws.ToString(input.AccessKeyId))
+				return &iam.DeleteAccessKeyOutput{}, nil
+			}
+			mockIAM.ListUserPoliciesFunc = func(ctx context.Context, input *iam.ListUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListUserPoliciesOutput, error) {
+				return &iam.ListUserPoliciesOutput{PolicyNames: []string{"test-bucket"}}, nil
+			}
+			mockIAM.DeleteUserPolicyFunc = func(ctx context.Context, input *iam.DeleteUserPolicyInput, opts ...func(*iam.Options)) (*iam.DeleteUserPolicyOutput, error) {
+				calls = append(calls, "DeleteUserPolicy "+aws.ToString(input.PolicyName))
+				return &iam.DeleteUserPolicyOutput{}, nil
+			}
+			mockIAM.ListAttachedUserPoliciesFunc = func(ctx context.Context, input *iam.ListAttachedUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListAttachedUserPoliciesOutput, error) {
+				return &iam.ListAttachedUserPoliciesOutput{
+					AttachedPolicies: []types.AttachedPolicy{{PolicyArn: aws.String("arn:aws:iam::123456789012:policy/shared")}},
+				}, nil
+			}
+			mockIAM.DetachUserPolicyFunc = func(ctx context.Context, input *iam.DetachUserPolicyInput, opts ...func(*iam.Options)) (*iam.DetachUserPolicyOutput, error) {
+				calls = append(calls, "DetachUserPolicy "+aws.ToString(input.PolicyArn))
+				return &iam.DetachUserPolicyOutput{}, nil
+			}
+			mockIAM.DeleteUserFunc = func(ctx context.Context, input *iam.DeleteUserInput, opts ...func(*iam.Options)) (*iam.DeleteUserOutput, error) {
+				Expect(input.UserName).To(Equal(aws.String("test-user")))
+				calls = append(calls, "DeleteUser")
+				return &iam.DeleteUserOutput{}, nil
+			}
+
+			err := client.RevokeBucketAccess(ctx, "test-user")
+			Expect(err).To(BeNil())
+			Expect(calls).To(Equal([]string{
+				"DeleteAccessKey key-1",
+				"DeleteAccessKey key-2",
+				"DeleteUserPolicy test-bucket",
+				"DetachUserPolicy arn:aws:iam::123456789012:policy/shared",
+				"DeleteUser",
+			}))
+		})
+
+		It("should succeed when the user does not exist", func(ctx SpecContext) {
+			mockIAM.ListAccessKeysFunc = func(ctx context.Context, input *iam.ListAccessKeysInput, opts ...func(*iam.Options)) (*iam.ListAccessKeysOutput, error) {
+				return nil, &types.NoSuchEntityException{}
+			}
+			mockIAM.DeleteUserFunc = func(ctx context.Context, input *iam.DeleteUserInput, opts ...func(*iam.Options)) (*iam.DeleteUserOutput, error) {
+				Fail("DeleteUser should not be called")
+				return nil, nil
+			}
+
+			err := client.RevokeBucketAccess(ctx, "test-user")
+			Expect(err).To(BeNil())
+		})
+
+		It("should succeed when the user disappears during the revocation", func(ctx SpecContext) {
+			mockIAM.ListUserPoliciesFunc = func(ctx context.Context, input *iam.ListUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListUserPoliciesOutput, error) {
+				return &iam.ListUserPoliciesOutput{PolicyNames: []string{"test-bucket"}}, nil
+			}
+			mockIAM.DeleteUserPolicyFunc = func(ctx context.Context, input *iam.DeleteUserPolicyInput, opts ...func(*iam.Options)) (*iam.DeleteUserPolicyOutput, error) {
+				return nil, &types.NoSuchEntityException{}
+			}
+			mockIAM.DeleteUserFunc = func(ctx context.Context, input *iam.DeleteUserInput, opts ...func(*iam.Options)) (*iam.DeleteUserOutput, error) {
+				return nil, &types.NoSuchEntityException{}
+			}
+
+			err := client.RevokeBucketAccess(ctx, "test-user")
+			Expect(err).To(BeNil())
+		})
+
+		It("should return an error when the user cannot be deleted", func(ctx SpecContext) {
+			mockIAM.DeleteUserFunc = func(ctx context.Context, input *iam.DeleteUserInput, opts ...func(*iam.Options)) (*iam.DeleteUserOutput, error) {
+				return nil, &types.DeleteConflictException{}
+			}
+
+			err := client.RevokeBucketAccess(ctx, "test-user")
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("failed to delete IAM user test-user"))
+		})
+
+		It("should return an error when a policy cannot be detached", func(ctx SpecContext) {
+			mockIAM.ListAttachedUserPoliciesFunc = func(ctx context.Context, input *iam.ListAttachedUserPoliciesInput, opts ...func(*iam.Options)) (*iam.ListAttachedUserPoliciesOutput, error) {
+				return &iam.ListAttachedUserPoliciesOutput{
+					AttachedPolicies: []types.AttachedPolicy{{PolicyArn: aws.String("arn:aws:iam::123456789012:policy/shared")}},
+				}, nil
+			}
+			mockIAM.DetachUserPolicyFunc = func(ctx context.Context, input *iam.DetachUserPolicyInput, opts ...func(*iam.Options)) (*iam.DetachUserPolicyOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			err := client.RevokeBucketAccess(ctx, "test-user")
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("failed to detach policy"))
+		})
+	})
+
+	Describe("CreateBucketAccessRole", func() {
+		var (
+			mockIAM     *MockIAMClient
+			client      *iamclient.IAMClient
+			trustPolicy string
+		)
+
+		BeforeEach(func() {
+			mockIAM = &MockIAMClient{}
+			client = &iamclient.IAMClient{IAMService: mockIAM}
+			trustPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Federated":"arn:aws:iam::123456789012:oidc-provider/oidc.example.com"},"Action":"sts:AssumeRoleWithWebIdentity"}]}`
+		})
+
+		It("should create a role with the trust policy and attach the bucket policy", func(ctx SpecContext) {
+			mockIAM.CreateRoleFunc = func(ctx context.Context, input *iam.CreateRoleInput, opts ...func(*iam.Options)) (*iam.CreateRoleOutput, error) {
+				Expect(input.RoleName).To(Equal(aws.String("test-role")))
+				Expect(input.AssumeRolePolicyDocument).To(Equal(aws.String(trustPolicy)))
+				return &iam.CreateRoleOutput{Role: &types.Role{Arn: aws.String("arn:aws:iam::123456789012:role/test-role")}}, nil
+			}
+			mockIAM.PutRolePolicyFunc = func(ctx context.Context, input *iam.PutRolePolicyInput, opts ...func(*iam.Options)) (*iam.PutRolePolicyOutput, error) {
+				Expect(input.RoleName).To(Equal(aws.String("test-role")))
+				Expect(input.PolicyName).To(Equal(aws.String("test-bucket")))
+				Expect(input.PolicyDocument).To(Equal(aws.String(testPolicy)))
+				return &iam.PutRolePolicyOutput{}, nil
+			}
+
+			role, err := client.CreateBucketAccessRole(ctx, "test-role", "test-bucket", trustPolicy, testPolicy)
+			Expect(err).To(BeNil())
+			Expect(role.Arn).To(Equal(aws.String("arn:aws:iam::123456789012:role/test-role")))
+		})
+
+		It("should update the trust policy of an existing role", func(ctx SpecContext) {
+			mockIAM.CreateRoleFunc = func(ctx context.Context, input *iam.CreateRoleInput, opts ...func(*iam.Options)) (*iam.CreateRoleOutput, error) {
+				return nil, &types.EntityAlreadyExistsException{}
+			}
+			updated := false
+			mockIAM.UpdateAssumeRolePolicyFunc = func(ctx context.Context, input *iam.UpdateAssumeRolePolicyInput, opts ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error) {
+				Expect(input.PolicyDocument).To(Equal(aws.String(trustPolicy)))
+				updated = true
+				return &iam.UpdateAssumeRolePolicyOutput{}, nil
+			}
+
+			role, err := client.CreateBucketAccessRole(ctx, "test-role", "test-bucket", trustPolicy, testPolicy)
+			Expect(err).To(BeNil())
+			Expect(updated).To(BeTrue())
+			Expect(role.Arn).To(Equal(aws.String("arn:aws:iam::123456789012:role/test-role")))
+		})
+
+		It("should return an error when the role cannot be created", func(ctx SpecContext) {
+			mockIAM.CreateRoleFunc = func(ctx context.Context, input *iam.CreateRoleInput, opts ...func(*iam.Options)) (*iam.CreateRoleOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			role, err := client.CreateBucketAccessRole(ctx, "test-role", "test-bucket", trustPolicy, testPolicy)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("failed to create IAM role test-role"))
+			Expect(role).To(BeNil())
+		})
+
+		It("should return an error when the policy cannot be attached", func(ctx SpecContext) {
+			mockIAM.PutRolePolicyFunc = func(ctx context.Context, input *iam.PutRolePolicyInput, opts ...func(*iam.Options)) (*iam.PutRolePolicyOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			role, err := client.CreateBucketAccessRole(ctx, "test-role", "test-bucket", trustPolicy, testPolicy)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("failed to attach policy to IAM role test-role"))
+			Expect(role).To(BeNil())
+		})
+	})
+
+	Describe("RevokeBucketAccessRole", func() {
+		var (
+			mockIAM *MockIAMClient
+			client  *iamclient.IAMClient
+		)
+
+		BeforeEach(func() {
+			mockIAM = &MockIAMClient{}
+			client = &iamclient.IAMClient{IAMService: mockIAM}
+		})
+
+		It("should delete inline policies, detach managed policies and delete the role", func(ctx SpecContext) {
+			var calls []string
+			mockIAM.ListRolePoliciesFunc = func(ctx context.Context, input *iam.ListRolePoliciesInput, opts ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error) {
+				return &iam.ListRolePoliciesOutput{PolicyNames: []string{"test-bucket"}}, nil
+			}
+			mockIAM.DeleteRolePolicyFunc = func(ctx context.Context, input *iam.DeleteRolePolicyInput, opts ...func(*iam.Options)) (*iam.DeleteRolePolicyOutput, error) {
+				calls = append(calls, "DeleteRolePolicy "+aws.ToString(input.PolicyName))
+				return &iam.DeleteRolePolicyOutput{}, nil
+			}
+			mockIAM.ListAttachedRolePoliciesFunc = func(ctx context.Context, input *iam.ListAttachedRolePoliciesInput, opts ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error) {
+				return &iam.ListAttachedRolePoliciesOutput{
+					AttachedPolicies: []types.AttachedPolicy{{PolicyArn: aws.String("arn:aws:iam::123456789012:policy/shared")}},
+				}, nil
+			}
+			mockIAM.DetachRolePolicyFunc = func(ctx context.Context, input *iam.DetachRolePolicyInput, opts ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error) {
+				calls = append(calls, "DetachRolePolicy "+aws.ToString(input.PolicyArn))
+				return &iam.DetachRolePolicyOutput{}, nil
+			}
+			mockIAM.DeleteRoleFunc = func(ctx context.Context, input *iam.DeleteRoleInput, opts ...func(*iam.Options)) (*iam.DeleteRoleOutput, error) {
+				Expect(input.RoleName).To(Equal(aws.String("test-role")))
+				calls = append(calls, "DeleteRole")
+				return &iam.DeleteRoleOutput{}, nil
+			}
+
+			err := client.RevokeBucketAccessRole(ctx, "test-role")
+			Expect(err).To(BeNil())
+			Expect(calls).To(Equal([]string{
+				"DeleteRolePolicy test-bucket",
+				"DetachRolePolicy arn:aws:iam::123456789012:policy/shared",
+				"DeleteRole",
+			}))
+		})
+
+		It("should succeed when the role does not exist", func(ctx SpecContext) {
+			mockIAM.ListRolePoliciesFunc = func(ctx context.Context, input *iam.ListRolePoliciesInput, opts ...func(*iam.Options)) (*iam.ListRolePoliciesOutput, error) {
+				return nil, &types.NoSuchEntityException{}
+			}
+			mockIAM.DeleteRoleFunc = func(ctx context.Context, input *iam.DeleteRoleInput, opts ...func(*iam.Options)) (*iam.DeleteRoleOutput, error) {
+				Fail("DeleteRole should not be called")
+				return nil, nil
+			}
+
+			err := client.RevokeBucketAccessRole(ctx, "test-role")
+			Expect(err).To(BeNil())
+		})
+
+		It("should return an error when the role cannot be deleted", func(ctx SpecContext) {
+			mockIAM.DeleteRoleFunc = func(ctx context.Context, input *iam.DeleteRoleInput, opts ...func(*iam.Options)) (*iam.DeleteRoleOutput, error) {
+				return nil, &types.DeleteConflictException{}
+			}
+
+			err := client.RevokeBucketAccessRole(ctx, "test-role")
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("failed to delete IAM role test-role"))
+		})
+	})
+})
@@ -0,0 +1,156 @@
+package s3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// CredentialsSource tells where the credentials of the object storage provider come from.
+type CredentialsSource string
+
+const (
+	// CredentialsFromParams uses the AccessKey, SecretKey and SessionToken parameters.
+	CredentialsFromParams CredentialsSource = ""
+	// CredentialsFromFiles reads the credentials from the files of CredentialsDir, as mounted from a Kubernetes secret.
+	CredentialsFromFiles CredentialsSource = "file"
+	// CredentialsFromEnv reads the credentials from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY
+	// and AWS_SESSION_TOKEN environment variables of the driver.
+	CredentialsFromEnv CredentialsSource = "env"
+)
+
+const (
+	// names of the credential files, matching the keys of the object storage provider secret
+	AccessKeyFile    = "COSI_S3_ACCESS_KEY_ID"
+	SecretKeyFile    = "COSI_S3_SECRET_ACCESS_KEY"
+	SessionTokenFile = "COSI_S3_SESSION_TOKEN"
+
+	// credentials read from files or the environment are read again after this delay, to pick up rotations
+	credentialsRefreshInterval = 5 * time.Minute
+	defaultRoleSessionName     = "scality-cosi-driver"
+)
+
+// NewCredentialsProvider returns the provider of the credentials described by the parameters.
+// When RoleARN is set, these credentials are only used to assume the role with STS,
+// and the temporary credentials of the role are renewed before they expire.
+func NewCredentialsProvider(params S3Params, httpClient aws.HTTPClient) (aws.CredentialsProvider, error) {
+	var provider aws.CredentialsProvider
+	switch params.CredentialsSource {
+	case CredentialsFromParams:
+		if params.AccessKey == "" || params.SecretKey == "" {
+			return nil, fmt.Errorf("AWS credentials are missing")
+		}
+		provider = credentials.NewStaticCredentialsProvider(params.AccessKey, params.SecretKey, params.SessionToken)
+	case CredentialsFromFiles:
+		if params.CredentialsDir == "" {
+			return nil, fmt.Errorf("AWS credentials directory is missing")
+		}
+		provider = aws.NewCredentialsCache(&fileCredentialsProvider{dir: params.CredentialsDir})
+	case CredentialsFromEnv:
+		provider = aws.NewCredentialsCache(&envCredentialsProvider{})
+	default:
+		return nil, fmt.Errorf("unsupported credentials source: %s", params.CredentialsSource)
+	}
+
+	if params.RoleARN == "" {
+		return provider, nil
+	}
+
+	region := params.Region
+	if region == "" {
+		region = defaultRegion
+	}
+	stsClient := sts.New(sts.Options{
+		Region:       region,
+		Credentials:  provider,
+		HTTPClient:   httpClient,
+		BaseEndpoint: aws.String(stsEndpoint(params)),
+	})
+
+	sessionName := params.RoleSessionName
+	if sessionName == "" {
+		sessionName = defaultRoleSessionName
+	}
+	return aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, params.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = sessionName
+	})), nil
+}
+
+func stsEndpoint(params S3Params) string {
+	for _, endpoint := range []string{params.STSEndpoint, params.IAMEndpoint} {
+		if endpoint != "" {
+			return endpoint
+		}
+	}
+	return params.Endpoint
+}
+
+// fileCredentialsProvider reads credentials from the files of a directory
+type fileCredentialsProvider struct {
+	dir string
+}
+
+func (p *fileCredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	accessKey, err := p.read(AccessKeyFile)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	secretKey, err := p.read(SecretKeyFile)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	sessionToken, err := p.read(SessionTokenFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return aws.Credentials{}, err
+	}
+
+	if accessKey == "" || secretKey == "" {
+		return aws.Credentials{}, fmt.Errorf("AWS credentials are missing in %s", p.dir)
+	}
+
+	return aws.Credentials{
+		AccessKeyID:     accessKey,
+		SecretAccessKey: secretKey,
+		SessionToken:    sessionToken,
+		Source:          "FileCredentialsProvider",
+		CanExpire:       true,
+		Expires:         time.Now().Add(credentialsRefreshInterval),
+	}, nil
+}
+
+func (p *fileCredentialsProvider) read(name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(p.dir, name))
+	if err != nil {
+		return "", fmt.Errorf("failed to read AWS credentials: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// envCredentialsProvider reads credentials from the environment of the driver
+type envCredentialsProvider struct{}
+
+func (p *envCredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return aws.Credentials{}, fmt.Errorf("AWS credentials are missing in the environment")
+	}
+
+	return aws.Credentials{
+		AccessKeyID:     accessKey,
+		SecretAccessKey: secretKey,
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		Source:          "EnvCredentialsProvider",
+		CanExpire:       true,
+		Expires:         time.Now().Add(credentialsRefreshInterval),
+	}, nil
+}
@@ -0,0 +1,113 @@
+package s3client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/smithy-go"
+	"k8s.io/klog/v2"
+)
+
+// QuotaAPI covers Scality's bucket quota extension to the S3 API, which the AWS SDK does not implement.
+type QuotaAPI interface {
+	PutBucketQuota(ctx context.Context, bucketName string, quota int64) error
+}
+
+type quotaConfiguration struct {
+	XMLName xml.Name `xml:"QuotaConfiguration"`
+	Quota   int64    `xml:"Quota"`
+}
+
+type errorResponse struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// quotaClient sends SigV4 signed bucket quota requests to the S3 endpoint.
+type quotaClient struct {
+	httpClient  aws.HTTPClient
+	endpoint    string
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+}
+
+func newQuotaClient(awsCfg aws.Config, endpoint string) *quotaClient {
+	return &quotaClient{
+		httpClient:  awsCfg.HTTPClient,
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		region:      awsCfg.Region,
+		credentials: awsCfg.Credentials,
+		signer:      v4.NewSigner(),
+	}
+}
+
+// PutBucketQuota sets the maximum size in bytes of a bucket with a PUT /<bucket>?quota request.
+func (client *quotaClient) PutBucketQuota(ctx context.Context, bucketName string, quota int64) error {
+	body, err := xml.Marshal(quotaConfiguration{Quota: quota})
+	if err != nil {
+		return fmt.Errorf("failed to encode bucket quota: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, client.endpoint+"/"+url.PathEscape(bucketName)+"?quota", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build bucket quota request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/xml")
+
+	if err := client.sign(ctx, req, body); err != nil {
+		return err
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return &smithy.OperationError{ServiceID: "S3", OperationName: "PutBucketQuota", Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &smithy.OperationError{ServiceID: "S3", OperationName: "PutBucketQuota", Err: parseErrorResponse(resp)}
+	}
+	return nil
+}
+
+func (client *quotaClient) sign(ctx context.Context, req *http.Request, body []byte) error {
+	credentials, err := client.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve credentials: %w", err)
+	}
+
+	payloadHash := sha256.Sum256(body)
+	payloadHashHex := hex.EncodeToString(payloadHash[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHashHex)
+
+	if err := client.signer.SignHTTP(ctx, credentials, req, payloadHashHex, "s3", client.region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign bucket quota request: %w", err)
+	}
+	return nil
+}
+
+// parseErrorResponse turns an S3 XML error document into an API error, so that callers can check its code.
+func parseErrorResponse(resp *http.Response) error {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		klog.V(4).InfoS("Failed to read error response body", "status", resp.StatusCode, "error", err)
+	}
+
+	var errResp errorResponse
+	if err := xml.Unmarshal(data, &errResp); err != nil || errResp.Code == "" {
+		return &smithy.GenericAPIError{Code: http.StatusText(resp.StatusCode), Message: strings.TrimSpace(string(data))}
+	}
+	return &smithy.GenericAPIError{Code: errResp.Code, Message: errResp.Message}
+}
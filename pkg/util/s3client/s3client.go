@@ -2,50 +2,96 @@ package s3client
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
-	"strings"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/aws/smithy-go/logging"
 	"k8s.io/klog/v2"
 )
 
 type S3API interface {
 	CreateBucket(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error)
+	DeleteBucket(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error)
+	ListObjectVersions(ctx context.Context, input *s3.ListObjectVersionsInput, opts ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error)
+	DeleteObjects(ctx context.Context, input *s3.DeleteObjectsInput, opts ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+	ListMultipartUploads(ctx context.Context, input *s3.ListMultipartUploadsInput, opts ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error)
+	AbortMultipartUpload(ctx context.Context, input *s3.AbortMultipartUploadInput, opts ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+	PutBucketVersioning(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error)
+	GetBucketVersioning(ctx context.Context, input *s3.GetBucketVersioningInput, opts ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error)
+	PutObjectLockConfiguration(ctx context.Context, input *s3.PutObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.PutObjectLockConfigurationOutput, error)
+	GetObjectLockConfiguration(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error)
+	PutBucketEncryption(ctx context.Context, input *s3.PutBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.PutBucketEncryptionOutput, error)
+	GetBucketEncryption(ctx context.Context, input *s3.GetBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error)
+	PutBucketLifecycleConfiguration(ctx context.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error)
+	GetBucketLifecycleConfiguration(ctx context.Context, input *s3.GetBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error)
+	PutBucketTagging(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error)
+	PutBucketCors(ctx context.Context, input *s3.PutBucketCorsInput, opts ...func(*s3.Options)) (*s3.PutBucketCorsOutput, error)
+	GetBucketCors(ctx context.Context, input *s3.GetBucketCorsInput, opts ...func(*s3.Options)) (*s3.GetBucketCorsOutput, error)
+	PutBucketPolicy(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error)
+	GetBucketPolicy(ctx context.Context, input *s3.GetBucketPolicyInput, opts ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error)
+	PutBucketReplication(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error)
+	GetBucketReplication(ctx context.Context, input *s3.GetBucketReplicationInput, opts ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error)
+	PutBucketNotificationConfiguration(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error)
+	GetBucketNotificationConfiguration(ctx context.Context, input *s3.GetBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketNotificationConfigurationOutput, error)
+	PutBucketAcl(ctx context.Context, input *s3.PutBucketAclInput, opts ...func(*s3.Options)) (*s3.PutBucketAclOutput, error)
+	PutPublicAccessBlock(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error)
+	HeadBucket(ctx context.Context, input *s3.HeadBucketInput, opts ...func(*s3.Options)) (*s3.HeadBucketOutput, error)
+	ListBuckets(ctx context.Context, input *s3.ListBucketsInput, opts ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
+	GetBucketLocation(ctx context.Context, input *s3.GetBucketLocationInput, opts ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error)
+	GetBucketTagging(ctx context.Context, input *s3.GetBucketTaggingInput, opts ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error)
 }
 
 const (
 	defaultRegion  = "us-east-1"
 	requestTimeout = 15 * time.Second
+	// maximum number of keys accepted by a single DeleteObjects request
+	deleteObjectsBatchSize = 1000
 )
 
 type S3Params struct {
-	AccessKey string
-	SecretKey string
-	Endpoint  string
-	Region    string
-	TLSCert   []byte // Optional field for TLS certificates
-	Debug     bool
+	AccessKey   string
+	SecretKey   string
+	Endpoint    string
+	IAMEndpoint string // Optional field, defaults to Endpoint for IAM operations
+	STSEndpoint string // Optional field, defaults to IAMEndpoint for STS operations
+	Region      string
+	TLSCert     []byte // Optional CA certificates of the endpoints, the system roots are used otherwise
+	Debug       bool
+
+	TLSInsecure   bool   // Disables TLS certificate verification, only when explicitly requested
+	TLSClientCert []byte // Optional PEM client certificate for mutual TLS, along with TLSClientKey
+	TLSClientKey  []byte // Optional PEM private key of TLSClientCert
+
+	SessionToken      string            // Optional session token of temporary credentials
+	CredentialsSource CredentialsSource // Where the credentials come from, AccessKey and SecretKey by default
+	CredentialsDir    string            // Directory of the credential files, for CredentialsFromFiles
+	RoleARN           string            // Optional role assumed with STS using the credentials
+	RoleSessionName   string            // Optional session name of the assumed role
+}
+
+// BucketOptions holds the bucket settings that can only be chosen at creation time.
+type BucketOptions struct {
+	ObjectLockEnabled bool
+	// ACL is the canned ACL of the bucket, the object storage default if empty
+	ACL types.BucketCannedACL
 }
 
 type S3Client struct {
-	S3Service S3API
+	S3Service    S3API
+	QuotaService QuotaAPI
+	httpClient   *http.Client
 }
 
 func InitS3Client(params S3Params) (*S3Client, error) {
-	if params.AccessKey == "" || params.SecretKey == "" {
-		return nil, fmt.Errorf("AWS credentials are missing")
-	}
-
 	var logger logging.Logger
 	if params.Debug {
 		logger = logging.NewStandardLogger(os.Stdout)
@@ -53,15 +99,14 @@ func InitS3Client(params S3Params) (*S3Client, error) {
 		logger = nil
 	}
 
-	httpClient := &http.Client{
-		Timeout: requestTimeout,
+	httpClient, err := NewHTTPClient(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure TLS: %w", err)
 	}
 
-	// in the case where endpoint is HTTPS but no certificate is provided, skip TLS validation
-	isHTTPSEndpoint := strings.HasPrefix(params.Endpoint, "https://")
-	skipTLSValidation := isHTTPSEndpoint && len(params.TLSCert) == 0
-	if isHTTPSEndpoint {
-		httpClient.Transport = ConfigureTLSTransport(params.TLSCert, skipTLSValidation)
+	credentialsProvider, err := NewCredentialsProvider(params, httpClient)
+	if err != nil {
+		return nil, err
 	}
 
 	region := params.Region
@@ -73,7 +118,7 @@ func InitS3Client(params S3Params) (*S3Client, error) {
 
 	awsCfg, err := config.LoadDefaultConfig(ctx,
 		config.WithRegion(region),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(params.AccessKey, params.SecretKey, "")),
+		config.WithCredentialsProvider(credentialsProvider),
 		config.WithHTTPClient(httpClient),
 		config.WithLogger(logger),
 	)
@@ -87,35 +132,33 @@ func InitS3Client(params S3Params) (*S3Client, error) {
 	})
 
 	return &S3Client{
-		S3Service: s3Client,
+		S3Service:    s3Client,
+		QuotaService: newQuotaClient(awsCfg, params.Endpoint),
+		httpClient:   httpClient,
 	}, nil
 }
 
-func ConfigureTLSTransport(certData []byte, skipTLSValidation bool) *http.Transport {
-	tlsSettings := &tls.Config{
-		MinVersion:         tls.VersionTLS12,
-		InsecureSkipVerify: skipTLSValidation,
-	}
-
-	if len(certData) > 0 {
-		caCertPool := x509.NewCertPool()
-		if ok := caCertPool.AppendCertsFromPEM(certData); !ok {
-			klog.Warning("Failed to append provided cert data to the certificate pool")
-		}
-		tlsSettings.RootCAs = caCertPool
-	}
-
-	return &http.Transport{
-		TLSClientConfig: tlsSettings,
+// CloseIdleConnections closes the idle connections of the client, once it is no longer used.
+func (client *S3Client) CloseIdleConnections() {
+	if client.httpClient != nil {
+		client.httpClient.CloseIdleConnections()
 	}
 }
 
-func (client *S3Client) CreateBucket(ctx context.Context, bucketName string, params S3Params) error {
+func (client *S3Client) CreateBucket(ctx context.Context, bucketName string, params S3Params, options BucketOptions) error {
 
 	input := &s3.CreateBucketInput{
 		Bucket: &bucketName,
 	}
 
+	if options.ObjectLockEnabled {
+		input.ObjectLockEnabledForBucket = aws.Bool(true)
+	}
+
+	if options.ACL != "" {
+		input.ACL = options.ACL
+	}
+
 	if params.Region != "us-east-1" {
 		input.CreateBucketConfiguration = &types.CreateBucketConfiguration{
 			LocationConstraint: types.BucketLocationConstraint(params.Region),
@@ -127,6 +170,496 @@ func (client *S3Client) CreateBucket(ctx context.Context, bucketName string, par
 		return err
 	}
 
-	klog.InfoS("Bucket creation operation succeeded", "name", bucketName, "region", params.Region)
+	klog.InfoS("Bucket creation operation succeeded", "name", bucketName, "region", params.Region, "objectLock", options.ObjectLockEnabled)
+	return nil
+}
+
+func (client *S3Client) DeleteBucket(ctx context.Context, bucketName string) error {
+	input := &s3.DeleteBucketInput{
+		Bucket: &bucketName,
+	}
+
+	_, err := client.S3Service.DeleteBucket(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	klog.InfoS("Bucket deletion operation succeeded", "name", bucketName)
+	return nil
+}
+
+// HeadBucket checks that a bucket exists and that the credentials can access it.
+func (client *S3Client) HeadBucket(ctx context.Context, bucketName string) error {
+	_, err := client.S3Service.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: &bucketName,
+	})
+	return err
+}
+
+// IsBucketOwner reports whether a bucket belongs to the account of the credentials,
+// by looking for it in the buckets listed for that account.
+func (client *S3Client) IsBucketOwner(ctx context.Context, bucketName string) (bool, error) {
+	paginator := s3.NewListBucketsPaginator(client.S3Service, &s3.ListBucketsInput{
+		Prefix: &bucketName,
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		// the prefix only narrows the listing on object storages supporting it
+		for _, bucket := range page.Buckets {
+			if aws.ToString(bucket.Name) == bucketName {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// DeleteObjectVersions removes every object version and delete marker of a bucket.
+// Listing always starts from the beginning, so an interrupted purge resumes on the next call.
+func (client *S3Client) DeleteObjectVersions(ctx context.Context, bucketName string) error {
+	paginator := s3.NewListObjectVersionsPaginator(client.S3Service, &s3.ListObjectVersionsInput{
+		Bucket:  &bucketName,
+		MaxKeys: aws.Int32(deleteObjectsBatchSize),
+	})
+
+	deleted := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		objects := make([]types.ObjectIdentifier, 0, len(page.Versions)+len(page.DeleteMarkers))
+		for _, version := range page.Versions {
+			objects = append(objects, types.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+		}
+		for _, marker := range page.DeleteMarkers {
+			objects = append(objects, types.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+		}
+
+		for start := 0; start < len(objects); start += deleteObjectsBatchSize {
+			end := min(start+deleteObjectsBatchSize, len(objects))
+			if err := client.deleteObjectBatch(ctx, bucketName, objects[start:end]); err != nil {
+				return err
+			}
+			deleted += end - start
+		}
+	}
+
+	klog.InfoS("Object versions deletion operation succeeded", "name", bucketName, "deleted", deleted)
+	return nil
+}
+
+func (client *S3Client) deleteObjectBatch(ctx context.Context, bucketName string, objects []types.ObjectIdentifier) error {
+	output, err := client.S3Service.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+		Bucket: &bucketName,
+		Delete: &types.Delete{
+			Objects: objects,
+			Quiet:   aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(output.Errors) > 0 {
+		first := output.Errors[0]
+		return fmt.Errorf("failed to delete %d objects from bucket %s, first error on key %s: %s: %s",
+			len(output.Errors), bucketName, aws.ToString(first.Key), aws.ToString(first.Code), aws.ToString(first.Message))
+	}
+	return nil
+}
+
+// AbortMultipartUploads aborts every in-progress multipart upload of a bucket.
+func (client *S3Client) AbortMultipartUploads(ctx context.Context, bucketName string) error {
+	paginator := s3.NewListMultipartUploadsPaginator(client.S3Service, &s3.ListMultipartUploadsInput{
+		Bucket: &bucketName,
+	})
+
+	aborted := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, upload := range page.Uploads {
+			_, err := client.S3Service.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   &bucketName,
+				Key:      upload.Key,
+				UploadId: upload.UploadId,
+			})
+			if err != nil {
+				var noSuchUpload *types.NoSuchUpload
+				if errors.As(err, &noSuchUpload) {
+					continue
+				}
+				return err
+			}
+			aborted++
+		}
+	}
+
+	klog.InfoS("Multipart uploads abort operation succeeded", "name", bucketName, "aborted", aborted)
+	return nil
+}
+
+// PutBucketVersioning sets the versioning state of a bucket.
+func (client *S3Client) PutBucketVersioning(ctx context.Context, bucketName string, versioningStatus types.BucketVersioningStatus) error {
+	_, err := client.S3Service.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+		Bucket: &bucketName,
+		VersioningConfiguration: &types.VersioningConfiguration{
+			Status: versioningStatus,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.InfoS("Bucket versioning operation succeeded", "name", bucketName, "status", versioningStatus)
+	return nil
+}
+
+// GetBucketVersioning returns the versioning state of a bucket, empty if versioning was never configured.
+func (client *S3Client) GetBucketVersioning(ctx context.Context, bucketName string) (types.BucketVersioningStatus, error) {
+	output, err := client.S3Service.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket: &bucketName,
+	})
+	if err != nil {
+		return "", err
+	}
+	return output.Status, nil
+}
+
+// PutObjectLockConfiguration sets the default retention of a bucket created with Object Lock enabled.
+// A nil retention keeps Object Lock enabled without a default retention rule.
+func (client *S3Client) PutObjectLockConfiguration(ctx context.Context, bucketName string, retention *types.DefaultRetention) error {
+	configuration := &types.ObjectLockConfiguration{
+		ObjectLockEnabled: types.ObjectLockEnabledEnabled,
+	}
+	if retention != nil {
+		configuration.Rule = &types.ObjectLockRule{DefaultRetention: retention}
+	}
+
+	_, err := client.S3Service.PutObjectLockConfiguration(ctx, &s3.PutObjectLockConfigurationInput{
+		Bucket:                  &bucketName,
+		ObjectLockConfiguration: configuration,
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.InfoS("Object lock configuration operation succeeded", "name", bucketName)
+	return nil
+}
+
+// GetObjectLockConfiguration returns the Object Lock configuration of a bucket, nil if Object Lock is not enabled.
+func (client *S3Client) GetObjectLockConfiguration(ctx context.Context, bucketName string) (*types.ObjectLockConfiguration, error) {
+	output, err := client.S3Service.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{
+		Bucket: &bucketName,
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "ObjectLockConfigurationNotFoundError" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return output.ObjectLockConfiguration, nil
+}
+
+// PutBucketEncryption sets the default server-side encryption of a bucket.
+func (client *S3Client) PutBucketEncryption(ctx context.Context, bucketName string, encryption *types.ServerSideEncryptionByDefault) error {
+	_, err := client.S3Service.PutBucketEncryption(ctx, &s3.PutBucketEncryptionInput{
+		Bucket: &bucketName,
+		ServerSideEncryptionConfiguration: &types.ServerSideEncryptionConfiguration{
+			Rules: []types.ServerSideEncryptionRule{
+				{ApplyServerSideEncryptionByDefault: encryption},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.InfoS("Bucket encryption operation succeeded", "name", bucketName, "algorithm", encryption.SSEAlgorithm)
+	return nil
+}
+
+// GetBucketEncryption returns the default server-side encryption of a bucket, nil if none is configured.
+func (client *S3Client) GetBucketEncryption(ctx context.Context, bucketName string) (*types.ServerSideEncryptionByDefault, error) {
+	output, err := client.S3Service.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{
+		Bucket: &bucketName,
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "ServerSideEncryptionConfigurationNotFoundError" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if output.ServerSideEncryptionConfiguration == nil {
+		return nil, nil
+	}
+	for _, rule := range output.ServerSideEncryptionConfiguration.Rules {
+		if rule.ApplyServerSideEncryptionByDefault != nil {
+			return rule.ApplyServerSideEncryptionByDefault, nil
+		}
+	}
+	return nil, nil
+}
+
+// PutBucketLifecycleConfiguration replaces the lifecycle rules of a bucket.
+func (client *S3Client) PutBucketLifecycleConfiguration(ctx context.Context, bucketName string, lifecycle *types.BucketLifecycleConfiguration) error {
+	_, err := client.S3Service.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 &bucketName,
+		LifecycleConfiguration: lifecycle,
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.InfoS("Bucket lifecycle configuration operation succeeded", "name", bucketName, "rules", len(lifecycle.Rules))
+	return nil
+}
+
+// GetBucketLifecycleConfiguration returns the lifecycle rules of a bucket, nil if none are configured.
+func (client *S3Client) GetBucketLifecycleConfiguration(ctx context.Context, bucketName string) ([]types.LifecycleRule, error) {
+	output, err := client.S3Service.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: &bucketName,
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchLifecycleConfiguration" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return output.Rules, nil
+}
+
+// PutBucketTagging replaces the tag set of a bucket.
+func (client *S3Client) PutBucketTagging(ctx context.Context, bucketName string, tags map[string]string) error {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tagSet := make([]types.Tag, 0, len(keys))
+	for _, key := range keys {
+		tagSet = append(tagSet, types.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+
+	_, err := client.S3Service.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+		Bucket:  &bucketName,
+		Tagging: &types.Tagging{TagSet: tagSet},
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.InfoS("Bucket tagging operation succeeded", "name", bucketName, "tags", len(tagSet))
+	return nil
+}
+
+// GetBucketTagging returns the tags of a bucket, nil if it has none.
+func (client *S3Client) GetBucketTagging(ctx context.Context, bucketName string) (map[string]string, error) {
+	output, err := client.S3Service.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
+		Bucket: &bucketName,
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchTagSet" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(output.TagSet))
+	for _, tag := range output.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
+// GetBucketLocation returns the region of a bucket, buckets without a location constraint being in us-east-1.
+func (client *S3Client) GetBucketLocation(ctx context.Context, bucketName string) (string, error) {
+	output, err := client.S3Service.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
+		Bucket: &bucketName,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if output.LocationConstraint == "" {
+		return defaultRegion, nil
+	}
+	return string(output.LocationConstraint), nil
+}
+
+// PutBucketCors replaces the CORS rules of a bucket.
+func (client *S3Client) PutBucketCors(ctx context.Context, bucketName string, rules []types.CORSRule) error {
+	_, err := client.S3Service.PutBucketCors(ctx, &s3.PutBucketCorsInput{
+		Bucket:            &bucketName,
+		CORSConfiguration: &types.CORSConfiguration{CORSRules: rules},
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.InfoS("Bucket CORS operation succeeded", "name", bucketName, "rules", len(rules))
+	return nil
+}
+
+// GetBucketCors returns the CORS rules of a bucket, nil if none are configured.
+func (client *S3Client) GetBucketCors(ctx context.Context, bucketName string) ([]types.CORSRule, error) {
+	output, err := client.S3Service.GetBucketCors(ctx, &s3.GetBucketCorsInput{
+		Bucket: &bucketName,
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchCORSConfiguration" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return output.CORSRules, nil
+}
+
+// PutBucketPolicy replaces the policy document of a bucket.
+func (client *S3Client) PutBucketPolicy(ctx context.Context, bucketName, policy string) error {
+	_, err := client.S3Service.PutBucketPolicy(ctx, &s3.PutBucketPolicyInput{
+		Bucket: &bucketName,
+		Policy: &policy,
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.InfoS("Bucket policy operation succeeded", "name", bucketName)
+	return nil
+}
+
+// GetBucketPolicy returns the policy document of a bucket, empty if none is attached.
+func (client *S3Client) GetBucketPolicy(ctx context.Context, bucketName string) (string, error) {
+	output, err := client.S3Service.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{
+		Bucket: &bucketName,
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchBucketPolicy" {
+			return "", nil
+		}
+		return "", err
+	}
+	return aws.ToString(output.Policy), nil
+}
+
+// PutBucketReplication replaces the replication configuration of a bucket, which must have versioning enabled.
+func (client *S3Client) PutBucketReplication(ctx context.Context, bucketName string, replication *types.ReplicationConfiguration) error {
+	_, err := client.S3Service.PutBucketReplication(ctx, &s3.PutBucketReplicationInput{
+		Bucket:                   &bucketName,
+		ReplicationConfiguration: replication,
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.InfoS("Bucket replication operation succeeded", "name", bucketName, "rules", len(replication.Rules))
+	return nil
+}
+
+// GetBucketReplication returns the replication configuration of a bucket, nil if none is configured.
+func (client *S3Client) GetBucketReplication(ctx context.Context, bucketName string) (*types.ReplicationConfiguration, error) {
+	output, err := client.S3Service.GetBucketReplication(ctx, &s3.GetBucketReplicationInput{
+		Bucket: &bucketName,
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "ReplicationConfigurationNotFoundError" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return output.ReplicationConfiguration, nil
+}
+
+// PutBucketNotificationConfiguration replaces the event notifications of a bucket.
+func (client *S3Client) PutBucketNotificationConfiguration(ctx context.Context, bucketName string, notification *types.NotificationConfiguration) error {
+	_, err := client.S3Service.PutBucketNotificationConfiguration(ctx, &s3.PutBucketNotificationConfigurationInput{
+		Bucket:                    &bucketName,
+		NotificationConfiguration: notification,
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.InfoS("Bucket notification operation succeeded", "name", bucketName, "queues", len(notification.QueueConfigurations))
+	return nil
+}
+
+// GetBucketNotificationConfiguration returns the event notifications of a bucket, nil if none are configured.
+func (client *S3Client) GetBucketNotificationConfiguration(ctx context.Context, bucketName string) (*types.NotificationConfiguration, error) {
+	output, err := client.S3Service.GetBucketNotificationConfiguration(ctx, &s3.GetBucketNotificationConfigurationInput{
+		Bucket: &bucketName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(output.QueueConfigurations) == 0 && len(output.TopicConfigurations) == 0 &&
+		len(output.LambdaFunctionConfigurations) == 0 && output.EventBridgeConfiguration == nil {
+		return nil, nil
+	}
+	return &types.NotificationConfiguration{
+		QueueConfigurations:          output.QueueConfigurations,
+		TopicConfigurations:          output.TopicConfigurations,
+		LambdaFunctionConfigurations: output.LambdaFunctionConfigurations,
+		EventBridgeConfiguration:     output.EventBridgeConfiguration,
+	}, nil
+}
+
+// PutBucketAcl replaces the ACL of a bucket with a canned ACL.
+func (client *S3Client) PutBucketAcl(ctx context.Context, bucketName string, acl types.BucketCannedACL) error {
+	_, err := client.S3Service.PutBucketAcl(ctx, &s3.PutBucketAclInput{
+		Bucket: &bucketName,
+		ACL:    acl,
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.InfoS("Bucket ACL operation succeeded", "name", bucketName, "acl", acl)
+	return nil
+}
+
+// PutPublicAccessBlock replaces the public access block configuration of a bucket.
+func (client *S3Client) PutPublicAccessBlock(ctx context.Context, bucketName string, block *types.PublicAccessBlockConfiguration) error {
+	_, err := client.S3Service.PutPublicAccessBlock(ctx, &s3.PutPublicAccessBlockInput{
+		Bucket:                         &bucketName,
+		PublicAccessBlockConfiguration: block,
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.InfoS("Bucket public access block operation succeeded", "name", bucketName)
+	return nil
+}
+
+// PutBucketQuota sets the maximum size in bytes of a bucket.
+func (client *S3Client) PutBucketQuota(ctx context.Context, bucketName string, quota int64) error {
+	if err := client.QuotaService.PutBucketQuota(ctx, bucketName, quota); err != nil {
+		return err
+	}
+
+	klog.InfoS("Bucket quota operation succeeded", "name", bucketName, "quota", quota)
 	return nil
 }
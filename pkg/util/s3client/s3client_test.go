@@ -2,12 +2,28 @@ package s3client_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/scality/cosi/pkg/util/s3client"
@@ -15,7 +31,35 @@ import (
 
 // MockS3Client implements the S3API interface for testing
 type MockS3Client struct {
-	CreateBucketFunc func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error)
+	CreateBucketFunc                       func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error)
+	DeleteBucketFunc                       func(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error)
+	ListObjectVersionsFunc                 func(ctx context.Context, input *s3.ListObjectVersionsInput, opts ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error)
+	DeleteObjectsFunc                      func(ctx context.Context, input *s3.DeleteObjectsInput, opts ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+	ListMultipartUploadsFunc               func(ctx context.Context, input *s3.ListMultipartUploadsInput, opts ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error)
+	AbortMultipartUploadFunc               func(ctx context.Context, input *s3.AbortMultipartUploadInput, opts ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+	PutBucketVersioningFunc                func(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error)
+	GetBucketVersioningFunc                func(ctx context.Context, input *s3.GetBucketVersioningInput, opts ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error)
+	PutObjectLockConfigurationFunc         func(ctx context.Context, input *s3.PutObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.PutObjectLockConfigurationOutput, error)
+	GetObjectLockConfigurationFunc         func(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error)
+	PutBucketEncryptionFunc                func(ctx context.Context, input *s3.PutBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.PutBucketEncryptionOutput, error)
+	GetBucketEncryptionFunc                func(ctx context.Context, input *s3.GetBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error)
+	PutBucketLifecycleConfigurationFunc    func(ctx context.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error)
+	PutBucketTaggingFunc                   func(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error)
+	PutBucketCorsFunc                      func(ctx context.Context, input *s3.PutBucketCorsInput, opts ...func(*s3.Options)) (*s3.PutBucketCorsOutput, error)
+	GetBucketCorsFunc                      func(ctx context.Context, input *s3.GetBucketCorsInput, opts ...func(*s3.Options)) (*s3.GetBucketCorsOutput, error)
+	PutBucketPolicyFunc                    func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error)
+	PutBucketReplicationFunc               func(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error)
+	PutBucketNotificationConfigurationFunc func(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error)
+	PutBucketAclFunc                       func(ctx context.Context, input *s3.PutBucketAclInput, opts ...func(*s3.Options)) (*s3.PutBucketAclOutput, error)
+	PutPublicAccessBlockFunc               func(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error)
+	HeadBucketFunc                         func(ctx context.Context, input *s3.HeadBucketInput, opts ...func(*s3.Options)) (*s3.HeadBucketOutput, error)
+	ListBucketsFunc                        func(ctx context.Context, input *s3.ListBucketsInput, opts ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
+	GetBucketLocationFunc                  func(ctx context.Context, input *s3.GetBucketLocationInput, opts ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error)
+	GetBucketTaggingFunc                   func(ctx context.Context, input *s3.GetBucketTaggingInput, opts ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error)
+	GetBucketLifecycleConfigurationFunc    func(ctx context.Context, input *s3.GetBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error)
+	GetBucketPolicyFunc                    func(ctx context.Context, input *s3.GetBucketPolicyInput, opts ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error)
+	GetBucketReplicationFunc               func(ctx context.Context, input *s3.GetBucketReplicationInput, opts ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error)
+	GetBucketNotificationConfigurationFunc func(ctx context.Context, input *s3.GetBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketNotificationConfigurationOutput, error)
 }
 
 func (m *MockS3Client) CreateBucket(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
@@ -25,6 +69,202 @@ func (m *MockS3Client) CreateBucket(ctx context.Context, input *s3.CreateBucketI
 	return &s3.CreateBucketOutput{}, nil
 }
 
+func (m *MockS3Client) DeleteBucket(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+	if m.DeleteBucketFunc != nil {
+		return m.DeleteBucketFunc(ctx, input, opts...)
+	}
+	return &s3.DeleteBucketOutput{}, nil
+}
+
+func (m *MockS3Client) ListObjectVersions(ctx context.Context, input *s3.ListObjectVersionsInput, opts ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error) {
+	if m.ListObjectVersionsFunc != nil {
+		return m.ListObjectVersionsFunc(ctx, input, opts...)
+	}
+	return &s3.ListObjectVersionsOutput{}, nil
+}
+
+func (m *MockS3Client) DeleteObjects(ctx context.Context, input *s3.DeleteObjectsInput, opts ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	if m.DeleteObjectsFunc != nil {
+		return m.DeleteObjectsFunc(ctx, input, opts...)
+	}
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+func (m *MockS3Client) ListMultipartUploads(ctx context.Context, input *s3.ListMultipartUploadsInput, opts ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error) {
+	if m.ListMultipartUploadsFunc != nil {
+		return m.ListMultipartUploadsFunc(ctx, input, opts...)
+	}
+	return &s3.ListMultipartUploadsOutput{}, nil
+}
+
+func (m *MockS3Client) AbortMultipartUpload(ctx context.Context, input *s3.AbortMultipartUploadInput, opts ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	if m.AbortMultipartUploadFunc != nil {
+		return m.AbortMultipartUploadFunc(ctx, input, opts...)
+	}
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketVersioning(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error) {
+	if m.PutBucketVersioningFunc != nil {
+		return m.PutBucketVersioningFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketVersioningOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketVersioning(ctx context.Context, input *s3.GetBucketVersioningInput, opts ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+	if m.GetBucketVersioningFunc != nil {
+		return m.GetBucketVersioningFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketVersioningOutput{}, nil
+}
+
+func (m *MockS3Client) PutObjectLockConfiguration(ctx context.Context, input *s3.PutObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.PutObjectLockConfigurationOutput, error) {
+	if m.PutObjectLockConfigurationFunc != nil {
+		return m.PutObjectLockConfigurationFunc(ctx, input, opts...)
+	}
+	return &s3.PutObjectLockConfigurationOutput{}, nil
+}
+
+func (m *MockS3Client) GetObjectLockConfiguration(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+	if m.GetObjectLockConfigurationFunc != nil {
+		return m.GetObjectLockConfigurationFunc(ctx, input, opts...)
+	}
+	return &s3.GetObjectLockConfigurationOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketEncryption(ctx context.Context, input *s3.PutBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.PutBucketEncryptionOutput, error) {
+	if m.PutBucketEncryptionFunc != nil {
+		return m.PutBucketEncryptionFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketEncryptionOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketEncryption(ctx context.Context, input *s3.GetBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+	if m.GetBucketEncryptionFunc != nil {
+		return m.GetBucketEncryptionFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketEncryptionOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketLifecycleConfiguration(ctx context.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+	if m.PutBucketLifecycleConfigurationFunc != nil {
+		return m.PutBucketLifecycleConfigurationFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketLifecycleConfigurationOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketTagging(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+	if m.PutBucketTaggingFunc != nil {
+		return m.PutBucketTaggingFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketTaggingOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketCors(ctx context.Context, input *s3.PutBucketCorsInput, opts ...func(*s3.Options)) (*s3.PutBucketCorsOutput, error) {
+	if m.PutBucketCorsFunc != nil {
+		return m.PutBucketCorsFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketCorsOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketCors(ctx context.Context, input *s3.GetBucketCorsInput, opts ...func(*s3.Options)) (*s3.GetBucketCorsOutput, error) {
+	if m.GetBucketCorsFunc != nil {
+		return m.GetBucketCorsFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketCorsOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketPolicy(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+	if m.PutBucketPolicyFunc != nil {
+		return m.PutBucketPolicyFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketPolicyOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketReplication(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error) {
+	if m.PutBucketReplicationFunc != nil {
+		return m.PutBucketReplicationFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketReplicationOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketNotificationConfiguration(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error) {
+	if m.PutBucketNotificationConfigurationFunc != nil {
+		return m.PutBucketNotificationConfigurationFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketNotificationConfigurationOutput{}, nil
+}
+
+func (m *MockS3Client) PutBucketAcl(ctx context.Context, input *s3.PutBucketAclInput, opts ...func(*s3.Options)) (*s3.PutBucketAclOutput, error) {
+	if m.PutBucketAclFunc != nil {
+		return m.PutBucketAclFunc(ctx, input, opts...)
+	}
+	return &s3.PutBucketAclOutput{}, nil
+}
+
+func (m *MockS3Client) PutPublicAccessBlock(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error) {
+	if m.PutPublicAccessBlockFunc != nil {
+		return m.PutPublicAccessBlockFunc(ctx, input, opts...)
+	}
+	return &s3.PutPublicAccessBlockOutput{}, nil
+}
+
+func (m *MockS3Client) HeadBucket(ctx context.Context, input *s3.HeadBucketInput, opts ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+	if m.HeadBucketFunc != nil {
+		return m.HeadBucketFunc(ctx, input, opts...)
+	}
+	return &s3.HeadBucketOutput{}, nil
+}
+
+func (m *MockS3Client) ListBuckets(ctx context.Context, input *s3.ListBucketsInput, opts ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+	if m.ListBucketsFunc != nil {
+		return m.ListBucketsFunc(ctx, input, opts...)
+	}
+	return &s3.ListBucketsOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketLocation(ctx context.Context, input *s3.GetBucketLocationInput, opts ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+	if m.GetBucketLocationFunc != nil {
+		return m.GetBucketLocationFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketLocationOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketTagging(ctx context.Context, input *s3.GetBucketTaggingInput, opts ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+	if m.GetBucketTaggingFunc != nil {
+		return m.GetBucketTaggingFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketTaggingOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketLifecycleConfiguration(ctx context.Context, input *s3.GetBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+	if m.GetBucketLifecycleConfigurationFunc != nil {
+		return m.GetBucketLifecycleConfigurationFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketLifecycleConfigurationOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketPolicy(ctx context.Context, input *s3.GetBucketPolicyInput, opts ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error) {
+	if m.GetBucketPolicyFunc != nil {
+		return m.GetBucketPolicyFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketPolicyOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketReplication(ctx context.Context, input *s3.GetBucketReplicationInput, opts ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error) {
+	if m.GetBucketReplicationFunc != nil {
+		return m.GetBucketReplicationFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketReplicationOutput{}, nil
+}
+
+func (m *MockS3Client) GetBucketNotificationConfiguration(ctx context.Context, input *s3.GetBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketNotificationConfigurationOutput, error) {
+	if m.GetBucketNotificationConfigurationFunc != nil {
+		return m.GetBucketNotificationConfigurationFunc(ctx, input, opts...)
+	}
+	return &s3.GetBucketNotificationConfigurationOutput{}, nil
+}
+
 func TestS3Client(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "S3Client Suite")
@@ -72,37 +312,290 @@ var _ = Describe("S3Client", func() {
 		})
 	})
 
+	Describe("NewCredentialsProvider", func() {
+		It("should provide the static credentials with their session token", func(ctx SpecContext) {
+			params.SessionToken = "test-session-token"
+			provider, err := s3client.NewCredentialsProvider(params, http.DefaultClient)
+			Expect(err).To(BeNil())
+
+			creds, err := provider.Retrieve(ctx)
+			Expect(err).To(BeNil())
+			Expect(creds.AccessKeyID).To(Equal("test-access-key"))
+			Expect(creds.SecretAccessKey).To(Equal("test-secret-key"))
+			Expect(creds.SessionToken).To(Equal("test-session-token"))
+		})
+
+		It("should fail if static credentials are missing", func() {
+			params.SecretKey = ""
+			_, err := s3client.NewCredentialsProvider(params, http.DefaultClient)
+			Expect(err).To(MatchError("AWS credentials are missing"))
+		})
+
+		It("should fail for an unsupported credentials source", func() {
+			params.CredentialsSource = "vault"
+			_, err := s3client.NewCredentialsProvider(params, http.DefaultClient)
+			Expect(err).To(MatchError("unsupported credentials source: vault"))
+		})
+
+		Context("with mounted credential files", func() {
+			var dir string
+
+			BeforeEach(func() {
+				dir = GinkgoT().TempDir()
+				params = s3client.S3Params{
+					Endpoint:          "https://s3.mock.endpoint",
+					Region:            "us-west-2",
+					CredentialsSource: s3client.CredentialsFromFiles,
+					CredentialsDir:    dir,
+				}
+				Expect(os.WriteFile(filepath.Join(dir, s3client.AccessKeyFile), []byte("file-access-key\n"), 0o600)).To(Succeed())
+				Expect(os.WriteFile(filepath.Join(dir, s3client.SecretKeyFile), []byte("file-secret-key\n"), 0o600)).To(Succeed())
+			})
+
+			It("should read the credentials from the files", func(ctx SpecContext) {
+				Expect(os.WriteFile(filepath.Join(dir, s3client.SessionTokenFile), []byte("file-session-token"), 0o600)).To(Succeed())
+				provider, err := s3client.NewCredentialsProvider(params, http.DefaultClient)
+				Expect(err).To(BeNil())
+
+				creds, err := provider.Retrieve(ctx)
+				Expect(err).To(BeNil())
+				Expect(creds.AccessKeyID).To(Equal("file-access-key"))
+				Expect(creds.SecretAccessKey).To(Equal("file-secret-key"))
+				Expect(creds.SessionToken).To(Equal("file-session-token"))
+				Expect(creds.CanExpire).To(BeTrue())
+			})
+
+			It("should read the files again once the credentials are invalidated", func(ctx SpecContext) {
+				provider, err := s3client.NewCredentialsProvider(params, http.DefaultClient)
+				Expect(err).To(BeNil())
+				_, err = provider.Retrieve(ctx)
+				Expect(err).To(BeNil())
+
+				Expect(os.WriteFile(filepath.Join(dir, s3client.AccessKeyFile), []byte("rotated-access-key"), 0o600)).To(Succeed())
+				provider.(*aws.CredentialsCache).Invalidate()
+
+				creds, err := provider.Retrieve(ctx)
+				Expect(err).To(BeNil())
+				Expect(creds.AccessKeyID).To(Equal("rotated-access-key"))
+			})
+
+			It("should fail if a credential file is missing", func(ctx SpecContext) {
+				Expect(os.Remove(filepath.Join(dir, s3client.SecretKeyFile))).To(Succeed())
+				provider, err := s3client.NewCredentialsProvider(params, http.DefaultClient)
+				Expect(err).To(BeNil())
+
+				_, err = provider.Retrieve(ctx)
+				Expect(err).NotTo(BeNil())
+				Expect(err.Error()).To(ContainSubstring("failed to read AWS credentials"))
+			})
+
+			It("should fail without a credentials directory", func() {
+				params.CredentialsDir = ""
+				_, err := s3client.NewCredentialsProvider(params, http.DefaultClient)
+				Expect(err).To(MatchError("AWS credentials directory is missing"))
+			})
+		})
+
+		Context("with credentials from the environment", func() {
+			BeforeEach(func() {
+				params = s3client.S3Params{
+					Endpoint:          "https://s3.mock.endpoint",
+					Region:            "us-west-2",
+					CredentialsSource: s3client.CredentialsFromEnv,
+				}
+			})
+
+			It("should read the credentials from the environment", func(ctx SpecContext) {
+				GinkgoT().Setenv("AWS_ACCESS_KEY_ID", "env-access-key")
+				GinkgoT().Setenv("AWS_SECRET_ACCESS_KEY", "env-secret-key")
+				GinkgoT().Setenv("AWS_SESSION_TOKEN", "")
+				provider, err := s3client.NewCredentialsProvider(params, http.DefaultClient)
+				Expect(err).To(BeNil())
+
+				creds, err := provider.Retrieve(ctx)
+				Expect(err).To(BeNil())
+				Expect(creds.AccessKeyID).To(Equal("env-access-key"))
+				Expect(creds.SecretAccessKey).To(Equal("env-secret-key"))
+			})
+
+			It("should fail if the environment has no credentials", func(ctx SpecContext) {
+				GinkgoT().Setenv("AWS_ACCESS_KEY_ID", "")
+				GinkgoT().Setenv("AWS_SECRET_ACCESS_KEY", "")
+				provider, err := s3client.NewCredentialsProvider(params, http.DefaultClient)
+				Expect(err).To(BeNil())
+
+				_, err = provider.Retrieve(ctx)
+				Expect(err).NotTo(BeNil())
+				Expect(err.Error()).To(ContainSubstring("AWS credentials are missing in the environment"))
+			})
+		})
+
+		Context("with a role to assume", func() {
+			var (
+				server  *httptest.Server
+				handler http.HandlerFunc
+			)
+
+			BeforeEach(func() {
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					handler(w, r)
+				}))
+				params.STSEndpoint = server.URL
+				params.RoleARN = "arn:aws:iam::123456789012:role/cosi-provisioner"
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("should assume the role with the base credentials", func(ctx SpecContext) {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					defer GinkgoRecover()
+					Expect(r.Header.Get("Authorization")).To(HavePrefix("AWS4-HMAC-SHA256 Credential=test-access-key/"))
+					Expect(r.ParseForm()).To(Succeed())
+					Expect(r.PostForm.Get("Action")).To(Equal("AssumeRole"))
+					Expect(r.PostForm.Get("RoleArn")).To(Equal("arn:aws:iam::123456789012:role/cosi-provisioner"))
+					Expect(r.PostForm.Get("RoleSessionName")).To(Equal("scality-cosi-driver"))
+					_, _ = w.Write([]byte(`<AssumeRoleResponse><AssumeRoleResult><Credentials>` +
+						`<AccessKeyId>role-access-key</AccessKeyId><SecretAccessKey>role-secret-key</SecretAccessKey>` +
+						`<SessionToken>role-session-token</SessionToken><Expiration>2099-01-01T00:00:00Z</Expiration>` +
+						`</Credentials></AssumeRoleResult></AssumeRoleResponse>`))
+				}
+
+				provider, err := s3client.NewCredentialsProvider(params, http.DefaultClient)
+				Expect(err).To(BeNil())
+
+				creds, err := provider.Retrieve(ctx)
+				Expect(err).To(BeNil())
+				Expect(creds.AccessKeyID).To(Equal("role-access-key"))
+				Expect(creds.SecretAccessKey).To(Equal("role-secret-key"))
+				Expect(creds.SessionToken).To(Equal("role-session-token"))
+				Expect(creds.CanExpire).To(BeTrue())
+			})
+
+			It("should return the error of a denied role", func(ctx SpecContext) {
+				handler = func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusForbidden)
+					_, _ = w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>AccessDenied</Code>` +
+						`<Message>Not authorized</Message></Error></ErrorResponse>`))
+				}
+
+				provider, err := s3client.NewCredentialsProvider(params, http.DefaultClient)
+				Expect(err).To(BeNil())
+
+				_, err = provider.Retrieve(ctx)
+				Expect(err).NotTo(BeNil())
+				var apiErr smithy.APIError
+				Expect(errors.As(err, &apiErr)).To(BeTrue())
+				Expect(apiErr.ErrorCode()).To(Equal("AccessDenied"))
+			})
+		})
+	})
+
 	Describe("ConfigureTLSTransport", func() {
+		var caCert, clientCert, clientKey []byte
+
+		BeforeEach(func() {
+			caCert, _ = generateCertificate()
+			clientCert, clientKey = generateCertificate()
+		})
 
-		It("should configure TLS when certData is provided", func() {
-			// Fake certificate data
-			certData := []byte("fake-cert-data")
-			transport := s3client.ConfigureTLSTransport(certData, false)
+		It("should verify certificates against the system roots by default", func() {
+			transport, err := s3client.ConfigureTLSTransport(params)
+			Expect(err).To(BeNil())
+			Expect(transport.TLSClientConfig.InsecureSkipVerify).To(BeFalse())
+			Expect(transport.TLSClientConfig.RootCAs).To(BeNil())
+			Expect(transport.TLSClientConfig.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+			Expect(transport.Proxy).NotTo(BeNil())
+		})
 
-			Expect(transport).NotTo(BeNil())
-			Expect(transport.TLSClientConfig).NotTo(BeNil())
+		It("should verify certificates against the provided CA certificate", func() {
+			params.TLSCert = caCert
+			transport, err := s3client.ConfigureTLSTransport(params)
+			Expect(err).To(BeNil())
 			Expect(transport.TLSClientConfig.InsecureSkipVerify).To(BeFalse())
 			Expect(transport.TLSClientConfig.RootCAs).NotTo(BeNil())
 		})
 
-		It("should skip TLS validation when no certData is provided and skipTLSValidation is true", func() {
-			transport := s3client.ConfigureTLSTransport(nil, true)
+		It("should fail if the CA certificate cannot be parsed", func() {
+			params.TLSCert = []byte("fake-cert-data")
+			_, err := s3client.ConfigureTLSTransport(params)
+			Expect(err).To(MatchError(ContainSubstring("failed to parse the TLS CA certificate")))
+		})
 
-			Expect(transport).NotTo(BeNil())
-			Expect(transport.TLSClientConfig).NotTo(BeNil())
+		It("should skip TLS validation only when insecure TLS is requested", func() {
+			params.TLSInsecure = true
+			transport, err := s3client.ConfigureTLSTransport(params)
+			Expect(err).To(BeNil())
 			Expect(transport.TLSClientConfig.InsecureSkipVerify).To(BeTrue())
-			Expect(transport.TLSClientConfig.RootCAs).To(BeNil())
 		})
 
-		It("should not configure TLS when no certData is provided and skipTLSValidation is false", func() {
-			transport := s3client.ConfigureTLSTransport(nil, false)
+		It("should fail if insecure TLS is requested along with a CA certificate", func() {
+			params.TLSInsecure = true
+			params.TLSCert = caCert
+			_, err := s3client.ConfigureTLSTransport(params)
+			Expect(err).To(MatchError(ContainSubstring("cannot be used with insecure TLS")))
+		})
 
-			Expect(transport).NotTo(BeNil())
-			Expect(transport.TLSClientConfig).NotTo(BeNil())
-			Expect(transport.TLSClientConfig.InsecureSkipVerify).To(BeFalse())
-			Expect(transport.TLSClientConfig.RootCAs).To(BeNil())
+		It("should present the client certificate", func() {
+			params.TLSClientCert = clientCert
+			params.TLSClientKey = clientKey
+			transport, err := s3client.ConfigureTLSTransport(params)
+			Expect(err).To(BeNil())
+			Expect(transport.TLSClientConfig.Certificates).To(HaveLen(1))
+		})
+
+		It("should fail if the client key is missing", func() {
+			params.TLSClientCert = clientCert
+			_, err := s3client.ConfigureTLSTransport(params)
+			Expect(err).To(MatchError(ContainSubstring("requires both a certificate and a key")))
+		})
+
+		It("should fail if the client key does not match the certificate", func() {
+			_, otherKey := generateCertificate()
+			params.TLSClientCert = clientCert
+			params.TLSClientKey = otherKey
+			_, err := s3client.ConfigureTLSTransport(params)
+			Expect(err).To(MatchError(ContainSubstring("failed to parse the TLS client certificate")))
+		})
+
+		It("should make InitS3Client fail on an invalid TLS configuration", func() {
+			params.TLSCert = []byte("fake-cert-data")
+			client, err := s3client.InitS3Client(params)
+			Expect(err).To(MatchError(ContainSubstring("failed to configure TLS")))
+			Expect(client).To(BeNil())
+		})
+
+		It("should reject a server certificate that is not signed by a trusted CA", func(ctx SpecContext) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			params.Endpoint = server.URL
+			client, err := s3client.InitS3Client(params)
+			Expect(err).To(BeNil())
+
+			// certificate errors are retried by the SDK, a single attempt is enough here
+			_, err = client.S3Service.(*s3.Client).HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String("test-bucket")},
+				func(o *s3.Options) { o.RetryMaxAttempts = 1 })
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("certificate"))
 		})
 
+		It("should connect to a server whose CA certificate is provided", func(ctx SpecContext) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			params.Endpoint = server.URL
+			params.TLSCert = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+			client, err := s3client.InitS3Client(params)
+			Expect(err).To(BeNil())
+
+			Expect(client.HeadBucket(ctx, "test-bucket")).To(Succeed())
+		})
 	})
 
 	Describe("CreateBucket", func() {
@@ -124,7 +617,33 @@ var _ = Describe("S3Client", func() {
 			client, _ := s3client.InitS3Client(params)
 			client.S3Service = mockS3
 
-			err := client.CreateBucket(ctx, "new-bucket", params)
+			err := client.CreateBucket(ctx, "new-bucket", params, s3client.BucketOptions{})
+			Expect(err).To(BeNil())
+		})
+
+		It("should enable object lock when requested", func(ctx SpecContext) {
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				Expect(input.ObjectLockEnabledForBucket).To(Equal(aws.Bool(true)))
+				return &s3.CreateBucketOutput{}, nil
+			}
+
+			client, _ := s3client.InitS3Client(params)
+			client.S3Service = mockS3
+
+			err := client.CreateBucket(ctx, "new-bucket", params, s3client.BucketOptions{ObjectLockEnabled: true})
+			Expect(err).To(BeNil())
+		})
+
+		It("should set the canned ACL when requested", func(ctx SpecContext) {
+			mockS3.CreateBucketFunc = func(ctx context.Context, input *s3.CreateBucketInput, opts ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+				Expect(input.ACL).To(Equal(types.BucketCannedACLPrivate))
+				return &s3.CreateBucketOutput{}, nil
+			}
+
+			client, _ := s3client.InitS3Client(params)
+			client.S3Service = mockS3
+
+			err := client.CreateBucket(ctx, "new-bucket", params, s3client.BucketOptions{ACL: types.BucketCannedACLPrivate})
 			Expect(err).To(BeNil())
 		})
 
@@ -136,8 +655,1046 @@ var _ = Describe("S3Client", func() {
 			client, _ := s3client.InitS3Client(params)
 			client.S3Service = mockS3
 
-			err := client.CreateBucket(ctx, "new-bucket", params)
+			err := client.CreateBucket(ctx, "new-bucket", params, s3client.BucketOptions{})
 			Expect(err).NotTo(BeNil())
 		})
 	})
-})
+
+	Describe("PutBucketVersioning", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should set the versioning status of the bucket", func(ctx SpecContext) {
+			mockS3.PutBucketVersioningFunc = func(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				Expect(input.VersioningConfiguration.Status).To(Equal(types.BucketVersioningStatusSuspended))
+				return &s3.PutBucketVersioningOutput{}, nil
+			}
+
+			err := client.PutBucketVersioning(ctx, "test-bucket", types.BucketVersioningStatusSuspended)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return the error from the S3 service", func(ctx SpecContext) {
+			mockS3.PutBucketVersioningFunc = func(ctx context.Context, input *s3.PutBucketVersioningInput, opts ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			err := client.PutBucketVersioning(ctx, "test-bucket", types.BucketVersioningStatusEnabled)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetBucketVersioning", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should return the versioning status of the bucket", func(ctx SpecContext) {
+			mockS3.GetBucketVersioningFunc = func(ctx context.Context, input *s3.GetBucketVersioningInput, opts ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				return &s3.GetBucketVersioningOutput{Status: types.BucketVersioningStatusEnabled}, nil
+			}
+
+			versioning, err := client.GetBucketVersioning(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(versioning).To(Equal(types.BucketVersioningStatusEnabled))
+		})
+
+		It("should return an empty status when versioning was never configured", func(ctx SpecContext) {
+			versioning, err := client.GetBucketVersioning(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(versioning).To(BeEmpty())
+		})
+	})
+
+	Describe("PutObjectLockConfiguration", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should set the default retention rule", func(ctx SpecContext) {
+			retention := &types.DefaultRetention{Mode: types.ObjectLockRetentionModeCompliance, Days: aws.Int32(30)}
+			mockS3.PutObjectLockConfigurationFunc = func(ctx context.Context, input *s3.PutObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.PutObjectLockConfigurationOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				Expect(input.ObjectLockConfiguration.ObjectLockEnabled).To(Equal(types.ObjectLockEnabledEnabled))
+				Expect(input.ObjectLockConfiguration.Rule.DefaultRetention).To(Equal(retention))
+				return &s3.PutObjectLockConfigurationOutput{}, nil
+			}
+
+			err := client.PutObjectLockConfiguration(ctx, "test-bucket", retention)
+			Expect(err).To(BeNil())
+		})
+
+		It("should not set a rule without retention", func(ctx SpecContext) {
+			mockS3.PutObjectLockConfigurationFunc = func(ctx context.Context, input *s3.PutObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.PutObjectLockConfigurationOutput, error) {
+				Expect(input.ObjectLockConfiguration.Rule).To(BeNil())
+				return &s3.PutObjectLockConfigurationOutput{}, nil
+			}
+
+			err := client.PutObjectLockConfiguration(ctx, "test-bucket", nil)
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Describe("GetObjectLockConfiguration", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should return the object lock configuration of the bucket", func(ctx SpecContext) {
+			mockS3.GetObjectLockConfigurationFunc = func(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+				return &s3.GetObjectLockConfigurationOutput{
+					ObjectLockConfiguration: &types.ObjectLockConfiguration{ObjectLockEnabled: types.ObjectLockEnabledEnabled},
+				}, nil
+			}
+
+			configuration, err := client.GetObjectLockConfiguration(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(configuration.ObjectLockEnabled).To(Equal(types.ObjectLockEnabledEnabled))
+		})
+
+		It("should return nil when object lock is not enabled", func(ctx SpecContext) {
+			mockS3.GetObjectLockConfigurationFunc = func(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "ObjectLockConfigurationNotFoundError"}
+			}
+
+			configuration, err := client.GetObjectLockConfiguration(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(configuration).To(BeNil())
+		})
+
+		It("should return other errors", func(ctx SpecContext) {
+			mockS3.GetObjectLockConfigurationFunc = func(ctx context.Context, input *s3.GetObjectLockConfigurationInput, opts ...func(*s3.Options)) (*s3.GetObjectLockConfigurationOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			configuration, err := client.GetObjectLockConfiguration(ctx, "test-bucket")
+			Expect(err).NotTo(BeNil())
+			Expect(configuration).To(BeNil())
+		})
+	})
+
+	Describe("PutBucketEncryption", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should set the default encryption rule", func(ctx SpecContext) {
+			encryption := &types.ServerSideEncryptionByDefault{SSEAlgorithm: types.ServerSideEncryptionAes256}
+			mockS3.PutBucketEncryptionFunc = func(ctx context.Context, input *s3.PutBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.PutBucketEncryptionOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				Expect(input.ServerSideEncryptionConfiguration.Rules).To(Equal([]types.ServerSideEncryptionRule{
+					{ApplyServerSideEncryptionByDefault: encryption},
+				}))
+				return &s3.PutBucketEncryptionOutput{}, nil
+			}
+
+			err := client.PutBucketEncryption(ctx, "test-bucket", encryption)
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Describe("GetBucketEncryption", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should return the default encryption rule", func(ctx SpecContext) {
+			mockS3.GetBucketEncryptionFunc = func(ctx context.Context, input *s3.GetBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+				return &s3.GetBucketEncryptionOutput{ServerSideEncryptionConfiguration: &types.ServerSideEncryptionConfiguration{
+					Rules: []types.ServerSideEncryptionRule{
+						{BucketKeyEnabled: aws.Bool(true)},
+						{ApplyServerSideEncryptionByDefault: &types.ServerSideEncryptionByDefault{SSEAlgorithm: types.ServerSideEncryptionAes256}},
+					},
+				}}, nil
+			}
+
+			encryption, err := client.GetBucketEncryption(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(encryption.SSEAlgorithm).To(Equal(types.ServerSideEncryptionAes256))
+		})
+
+		It("should return nil when no encryption is configured", func(ctx SpecContext) {
+			mockS3.GetBucketEncryptionFunc = func(ctx context.Context, input *s3.GetBucketEncryptionInput, opts ...func(*s3.Options)) (*s3.GetBucketEncryptionOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "ServerSideEncryptionConfigurationNotFoundError"}
+			}
+
+			encryption, err := client.GetBucketEncryption(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(encryption).To(BeNil())
+		})
+	})
+
+	Describe("PutBucketLifecycleConfiguration", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should set the lifecycle rules of the bucket", func(ctx SpecContext) {
+			lifecycle := &types.BucketLifecycleConfiguration{Rules: []types.LifecycleRule{{
+				ID:         aws.String("expire"),
+				Status:     types.ExpirationStatusEnabled,
+				Expiration: &types.LifecycleExpiration{Days: aws.Int32(1)},
+			}}}
+			mockS3.PutBucketLifecycleConfigurationFunc = func(ctx context.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				Expect(input.LifecycleConfiguration).To(Equal(lifecycle))
+				return &s3.PutBucketLifecycleConfigurationOutput{}, nil
+			}
+
+			err := client.PutBucketLifecycleConfiguration(ctx, "test-bucket", lifecycle)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return the error from the S3 service", func(ctx SpecContext) {
+			mockS3.PutBucketLifecycleConfigurationFunc = func(ctx context.Context, input *s3.PutBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			err := client.PutBucketLifecycleConfiguration(ctx, "test-bucket", &types.BucketLifecycleConfiguration{})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetBucketLifecycleConfiguration", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should return the lifecycle rules of the bucket", func(ctx SpecContext) {
+			rules := []types.LifecycleRule{{ID: aws.String("expire"), Status: types.ExpirationStatusEnabled, Expiration: &types.LifecycleExpiration{Days: aws.Int32(30)}}}
+			mockS3.GetBucketLifecycleConfigurationFunc = func(ctx context.Context, input *s3.GetBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				return &s3.GetBucketLifecycleConfigurationOutput{Rules: rules}, nil
+			}
+
+			current, err := client.GetBucketLifecycleConfiguration(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(current).To(Equal(rules))
+		})
+
+		It("should return nil when no lifecycle rules are configured", func(ctx SpecContext) {
+			mockS3.GetBucketLifecycleConfigurationFunc = func(ctx context.Context, input *s3.GetBucketLifecycleConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "NoSuchLifecycleConfiguration"}
+			}
+
+			current, err := client.GetBucketLifecycleConfiguration(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(current).To(BeNil())
+		})
+	})
+
+	Describe("PutBucketTagging", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should set the tags of the bucket sorted by key", func(ctx SpecContext) {
+			mockS3.PutBucketTaggingFunc = func(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				Expect(input.Tagging.TagSet).To(Equal([]types.Tag{
+					{Key: aws.String("env"), Value: aws.String("prod")},
+					{Key: aws.String("team"), Value: aws.String("payments")},
+				}))
+				return &s3.PutBucketTaggingOutput{}, nil
+			}
+
+			err := client.PutBucketTagging(ctx, "test-bucket", map[string]string{"team": "payments", "env": "prod"})
+			Expect(err).To(BeNil())
+		})
+
+		It("should return the error from the S3 service", func(ctx SpecContext) {
+			mockS3.PutBucketTaggingFunc = func(ctx context.Context, input *s3.PutBucketTaggingInput, opts ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			err := client.PutBucketTagging(ctx, "test-bucket", map[string]string{"team": "payments"})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetBucketCors", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should return the CORS rules of the bucket", func(ctx SpecContext) {
+			rules := []types.CORSRule{{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}}
+			mockS3.GetBucketCorsFunc = func(ctx context.Context, input *s3.GetBucketCorsInput, opts ...func(*s3.Options)) (*s3.GetBucketCorsOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				return &s3.GetBucketCorsOutput{CORSRules: rules}, nil
+			}
+
+			current, err := client.GetBucketCors(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(current).To(Equal(rules))
+		})
+
+		It("should return nil when no CORS rules are configured", func(ctx SpecContext) {
+			mockS3.GetBucketCorsFunc = func(ctx context.Context, input *s3.GetBucketCorsInput, opts ...func(*s3.Options)) (*s3.GetBucketCorsOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "NoSuchCORSConfiguration"}
+			}
+
+			current, err := client.GetBucketCors(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(current).To(BeNil())
+		})
+	})
+
+	Describe("PutBucketPolicy", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should set the policy of the bucket", func(ctx SpecContext) {
+			mockS3.PutBucketPolicyFunc = func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				Expect(input.Policy).To(Equal(aws.String(`{"Version":"2012-10-17"}`)))
+				return &s3.PutBucketPolicyOutput{}, nil
+			}
+
+			err := client.PutBucketPolicy(ctx, "test-bucket", `{"Version":"2012-10-17"}`)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return the error from the S3 service", func(ctx SpecContext) {
+			mockS3.PutBucketPolicyFunc = func(ctx context.Context, input *s3.PutBucketPolicyInput, opts ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			err := client.PutBucketPolicy(ctx, "test-bucket", `{"Version":"2012-10-17"}`)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetBucketPolicy", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should return the policy of the bucket", func(ctx SpecContext) {
+			mockS3.GetBucketPolicyFunc = func(ctx context.Context, input *s3.GetBucketPolicyInput, opts ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				return &s3.GetBucketPolicyOutput{Policy: aws.String(`{"Version":"2012-10-17"}`)}, nil
+			}
+
+			policy, err := client.GetBucketPolicy(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(policy).To(Equal(`{"Version":"2012-10-17"}`))
+		})
+
+		It("should return an empty policy when none is attached", func(ctx SpecContext) {
+			mockS3.GetBucketPolicyFunc = func(ctx context.Context, input *s3.GetBucketPolicyInput, opts ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "NoSuchBucketPolicy"}
+			}
+
+			policy, err := client.GetBucketPolicy(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(policy).To(BeEmpty())
+		})
+	})
+
+	Describe("PutBucketReplication", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should set the replication configuration of the bucket", func(ctx SpecContext) {
+			replication := &types.ReplicationConfiguration{
+				Role: aws.String("arn:aws:iam::root:role/replication"),
+				Rules: []types.ReplicationRule{{
+					Prefix:      aws.String(""),
+					Status:      types.ReplicationRuleStatusEnabled,
+					Destination: &types.Destination{Bucket: aws.String("arn:aws:s3:::dest")},
+				}},
+			}
+			mockS3.PutBucketReplicationFunc = func(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				Expect(input.ReplicationConfiguration).To(Equal(replication))
+				return &s3.PutBucketReplicationOutput{}, nil
+			}
+
+			err := client.PutBucketReplication(ctx, "test-bucket", replication)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return the error from the S3 service", func(ctx SpecContext) {
+			mockS3.PutBucketReplicationFunc = func(ctx context.Context, input *s3.PutBucketReplicationInput, opts ...func(*s3.Options)) (*s3.PutBucketReplicationOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			err := client.PutBucketReplication(ctx, "test-bucket", &types.ReplicationConfiguration{})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetBucketReplication", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should return the replication configuration of the bucket", func(ctx SpecContext) {
+			replication := &types.ReplicationConfiguration{Role: aws.String("arn:aws:iam::root:role/replication")}
+			mockS3.GetBucketReplicationFunc = func(ctx context.Context, input *s3.GetBucketReplicationInput, opts ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				return &s3.GetBucketReplicationOutput{ReplicationConfiguration: replication}, nil
+			}
+
+			current, err := client.GetBucketReplication(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(current).To(Equal(replication))
+		})
+
+		It("should return nil when no replication is configured", func(ctx SpecContext) {
+			mockS3.GetBucketReplicationFunc = func(ctx context.Context, input *s3.GetBucketReplicationInput, opts ...func(*s3.Options)) (*s3.GetBucketReplicationOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "ReplicationConfigurationNotFoundError"}
+			}
+
+			current, err := client.GetBucketReplication(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(current).To(BeNil())
+		})
+	})
+
+	Describe("PutBucketNotificationConfiguration", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should set the notification configuration of the bucket", func(ctx SpecContext) {
+			notification := &types.NotificationConfiguration{
+				QueueConfigurations: []types.QueueConfiguration{{
+					QueueArn: aws.String("arn:scality:bucketnotif:::kafka-events"),
+					Events:   []types.Event{"s3:ObjectCreated:*"},
+				}},
+			}
+			mockS3.PutBucketNotificationConfigurationFunc = func(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				Expect(input.NotificationConfiguration).To(Equal(notification))
+				return &s3.PutBucketNotificationConfigurationOutput{}, nil
+			}
+
+			err := client.PutBucketNotificationConfiguration(ctx, "test-bucket", notification)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return the error from the S3 service", func(ctx SpecContext) {
+			mockS3.PutBucketNotificationConfigurationFunc = func(ctx context.Context, input *s3.PutBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.PutBucketNotificationConfigurationOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			err := client.PutBucketNotificationConfiguration(ctx, "test-bucket", &types.NotificationConfiguration{})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetBucketNotificationConfiguration", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should return the event notifications of the bucket", func(ctx SpecContext) {
+			queues := []types.QueueConfiguration{{QueueArn: aws.String("arn:scality:bucketnotif:::kafka-events"), Events: []types.Event{types.EventS3ObjectCreated}}}
+			mockS3.GetBucketNotificationConfigurationFunc = func(ctx context.Context, input *s3.GetBucketNotificationConfigurationInput, opts ...func(*s3.Options)) (*s3.GetBucketNotificationConfigurationOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				return &s3.GetBucketNotificationConfigurationOutput{QueueConfigurations: queues}, nil
+			}
+
+			current, err := client.GetBucketNotificationConfiguration(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(current).To(Equal(&types.NotificationConfiguration{QueueConfigurations: queues}))
+		})
+
+		It("should return nil when no event notifications are configured", func(ctx SpecContext) {
+			current, err := client.GetBucketNotificationConfiguration(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(current).To(BeNil())
+		})
+	})
+
+	Describe("PutBucketAcl", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should set the canned ACL of the bucket", func(ctx SpecContext) {
+			mockS3.PutBucketAclFunc = func(ctx context.Context, input *s3.PutBucketAclInput, opts ...func(*s3.Options)) (*s3.PutBucketAclOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				Expect(input.ACL).To(Equal(types.BucketCannedACLPrivate))
+				return &s3.PutBucketAclOutput{}, nil
+			}
+
+			err := client.PutBucketAcl(ctx, "test-bucket", types.BucketCannedACLPrivate)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return the error from the S3 service", func(ctx SpecContext) {
+			mockS3.PutBucketAclFunc = func(ctx context.Context, input *s3.PutBucketAclInput, opts ...func(*s3.Options)) (*s3.PutBucketAclOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			err := client.PutBucketAcl(ctx, "test-bucket", types.BucketCannedACLPrivate)
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("PutPublicAccessBlock", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should set the public access block of the bucket", func(ctx SpecContext) {
+			block := &types.PublicAccessBlockConfiguration{BlockPublicAcls: aws.Bool(true), BlockPublicPolicy: aws.Bool(true)}
+			mockS3.PutPublicAccessBlockFunc = func(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				Expect(input.PublicAccessBlockConfiguration).To(Equal(block))
+				return &s3.PutPublicAccessBlockOutput{}, nil
+			}
+
+			err := client.PutPublicAccessBlock(ctx, "test-bucket", block)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return the error from the S3 service", func(ctx SpecContext) {
+			mockS3.PutPublicAccessBlockFunc = func(ctx context.Context, input *s3.PutPublicAccessBlockInput, opts ...func(*s3.Options)) (*s3.PutPublicAccessBlockOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			err := client.PutPublicAccessBlock(ctx, "test-bucket", &types.PublicAccessBlockConfiguration{})
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("HeadBucket", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should check the bucket", func(ctx SpecContext) {
+			mockS3.HeadBucketFunc = func(ctx context.Context, input *s3.HeadBucketInput, opts ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				return &s3.HeadBucketOutput{}, nil
+			}
+
+			err := client.HeadBucket(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+		})
+
+		It("should return the error from the S3 service", func(ctx SpecContext) {
+			mockS3.HeadBucketFunc = func(ctx context.Context, input *s3.HeadBucketInput, opts ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+				return nil, &types.NotFound{}
+			}
+
+			err := client.HeadBucket(ctx, "test-bucket")
+			var notFound *types.NotFound
+			Expect(errors.As(err, &notFound)).To(BeTrue())
+		})
+	})
+
+	Describe("IsBucketOwner", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should find the bucket in the following pages of the listing", func(ctx SpecContext) {
+			calls := 0
+			mockS3.ListBucketsFunc = func(ctx context.Context, input *s3.ListBucketsInput, opts ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+				Expect(input.Prefix).To(Equal(aws.String("test-bucket")))
+				calls++
+				if calls == 1 {
+					Expect(input.ContinuationToken).To(BeNil())
+					return &s3.ListBucketsOutput{
+						Buckets:           []types.Bucket{{Name: aws.String("test-bucket-1")}},
+						ContinuationToken: aws.String("next"),
+					}, nil
+				}
+				Expect(input.ContinuationToken).To(Equal(aws.String("next")))
+				return &s3.ListBucketsOutput{Buckets: []types.Bucket{{Name: aws.String("test-bucket")}}}, nil
+			}
+
+			owned, err := client.IsBucketOwner(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(owned).To(BeTrue())
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should return false when the bucket is not listed", func(ctx SpecContext) {
+			mockS3.ListBucketsFunc = func(ctx context.Context, input *s3.ListBucketsInput, opts ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+				return &s3.ListBucketsOutput{Buckets: []types.Bucket{{Name: aws.String("other-bucket")}}}, nil
+			}
+
+			owned, err := client.IsBucketOwner(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(owned).To(BeFalse())
+		})
+
+		It("should return the error from the S3 service", func(ctx SpecContext) {
+			mockS3.ListBucketsFunc = func(ctx context.Context, input *s3.ListBucketsInput, opts ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			_, err := client.IsBucketOwner(ctx, "test-bucket")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetBucketLocation", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should return the location constraint of the bucket", func(ctx SpecContext) {
+			mockS3.GetBucketLocationFunc = func(ctx context.Context, input *s3.GetBucketLocationInput, opts ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				return &s3.GetBucketLocationOutput{LocationConstraint: types.BucketLocationConstraintUsWest2}, nil
+			}
+
+			region, err := client.GetBucketLocation(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(region).To(Equal("us-west-2"))
+		})
+
+		It("should return us-east-1 for buckets without a location constraint", func(ctx SpecContext) {
+			mockS3.GetBucketLocationFunc = func(ctx context.Context, input *s3.GetBucketLocationInput, opts ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+				return &s3.GetBucketLocationOutput{}, nil
+			}
+
+			region, err := client.GetBucketLocation(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(region).To(Equal("us-east-1"))
+		})
+
+		It("should return the error from the S3 service", func(ctx SpecContext) {
+			mockS3.GetBucketLocationFunc = func(ctx context.Context, input *s3.GetBucketLocationInput, opts ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			_, err := client.GetBucketLocation(ctx, "test-bucket")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("GetBucketTagging", func() {
+		var mockS3 *MockS3Client
+		var client *s3client.S3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+			client, _ = s3client.InitS3Client(params)
+			client.S3Service = mockS3
+		})
+
+		It("should return the tags of the bucket", func(ctx SpecContext) {
+			mockS3.GetBucketTaggingFunc = func(ctx context.Context, input *s3.GetBucketTaggingInput, opts ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("test-bucket")))
+				return &s3.GetBucketTaggingOutput{TagSet: []types.Tag{{Key: aws.String("team"), Value: aws.String("payments")}}}, nil
+			}
+
+			tags, err := client.GetBucketTagging(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(tags).To(Equal(map[string]string{"team": "payments"}))
+		})
+
+		It("should return nil when the bucket has no tags", func(ctx SpecContext) {
+			mockS3.GetBucketTaggingFunc = func(ctx context.Context, input *s3.GetBucketTaggingInput, opts ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+				return nil, &smithy.GenericAPIError{Code: "NoSuchTagSet"}
+			}
+
+			tags, err := client.GetBucketTagging(ctx, "test-bucket")
+			Expect(err).To(BeNil())
+			Expect(tags).To(BeNil())
+		})
+
+		It("should return the error from the S3 service", func(ctx SpecContext) {
+			mockS3.GetBucketTaggingFunc = func(ctx context.Context, input *s3.GetBucketTaggingInput, opts ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			_, err := client.GetBucketTagging(ctx, "test-bucket")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("PutBucketQuota", func() {
+		var (
+			server   *httptest.Server
+			handler  http.HandlerFunc
+			client   *s3client.S3Client
+			endpoint string
+		)
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handler(w, r)
+			}))
+			endpoint = server.URL
+
+			var err error
+			quotaParams := params
+			quotaParams.Endpoint = endpoint
+			client, err = s3client.InitS3Client(quotaParams)
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should send a signed quota configuration for the bucket", func(ctx SpecContext) {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				defer GinkgoRecover()
+				Expect(r.Method).To(Equal(http.MethodPut))
+				Expect(r.URL.Path).To(Equal("/test-bucket"))
+				Expect(r.URL.Query().Has("quota")).To(BeTrue())
+				Expect(r.Header.Get("Authorization")).To(HavePrefix("AWS4-HMAC-SHA256 Credential=test-access-key/"))
+				body, err := io.ReadAll(r.Body)
+				Expect(err).To(BeNil())
+				Expect(string(body)).To(Equal("<QuotaConfiguration><Quota>1073741824</Quota></QuotaConfiguration>"))
+				w.WriteHeader(http.StatusOK)
+			}
+
+			err := client.PutBucketQuota(ctx, "test-bucket", 1073741824)
+			Expect(err).To(BeNil())
+		})
+
+		It("should return the S3 error code of a failed request", func(ctx SpecContext) {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte("<Error><Code>NoSuchBucket</Code><Message>The specified bucket does not exist.</Message></Error>"))
+			}
+
+			err := client.PutBucketQuota(ctx, "test-bucket", 1024)
+			Expect(err).NotTo(BeNil())
+			var apiErr smithy.APIError
+			Expect(errors.As(err, &apiErr)).To(BeTrue())
+			Expect(apiErr.ErrorCode()).To(Equal("NoSuchBucket"))
+		})
+
+		It("should return the HTTP status of a failed request without error document", func(ctx SpecContext) {
+			handler = func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotImplemented)
+			}
+
+			err := client.PutBucketQuota(ctx, "test-bucket", 1024)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("Not Implemented"))
+		})
+	})
+
+	Describe("DeleteBucket", func() {
+		var mockS3 *MockS3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+		})
+
+		It("should successfully delete a bucket", func(ctx SpecContext) {
+			mockS3.DeleteBucketFunc = func(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("old-bucket")))
+				return &s3.DeleteBucketOutput{}, nil
+			}
+
+			client, _ := s3client.InitS3Client(params)
+			client.S3Service = mockS3
+
+			err := client.DeleteBucket(ctx, "old-bucket")
+			Expect(err).To(BeNil())
+		})
+
+		It("should return the error from the S3 service", func(ctx SpecContext) {
+			mockS3.DeleteBucketFunc = func(ctx context.Context, input *s3.DeleteBucketInput, opts ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			client, _ := s3client.InitS3Client(params)
+			client.S3Service = mockS3
+
+			err := client.DeleteBucket(ctx, "old-bucket")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("DeleteObjectVersions", func() {
+		var mockS3 *MockS3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+		})
+
+		It("should delete all versions and delete markers across pages", func(ctx SpecContext) {
+			mockS3.ListObjectVersionsFunc = func(ctx context.Context, input *s3.ListObjectVersionsInput, opts ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("old-bucket")))
+				if input.KeyMarker == nil {
+					return &s3.ListObjectVersionsOutput{
+						IsTruncated:         aws.Bool(true),
+						NextKeyMarker:       aws.String("key-1"),
+						NextVersionIdMarker: aws.String("v1"),
+						Versions:            []types.ObjectVersion{{Key: aws.String("key-1"), VersionId: aws.String("v1")}},
+						DeleteMarkers:       []types.DeleteMarkerEntry{{Key: aws.String("key-1"), VersionId: aws.String("v2")}},
+					}, nil
+				}
+				return &s3.ListObjectVersionsOutput{
+					IsTruncated: aws.Bool(false),
+					Versions:    []types.ObjectVersion{{Key: aws.String("key-2"), VersionId: aws.String("null")}},
+				}, nil
+			}
+
+			var deleted []types.ObjectIdentifier
+			mockS3.DeleteObjectsFunc = func(ctx context.Context, input *s3.DeleteObjectsInput, opts ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+				Expect(input.Bucket).To(Equal(aws.String("old-bucket")))
+				deleted = append(deleted, input.Delete.Objects...)
+				return &s3.DeleteObjectsOutput{}, nil
+			}
+
+			client, _ := s3client.InitS3Client(params)
+			client.S3Service = mockS3
+
+			err := client.DeleteObjectVersions(ctx, "old-bucket")
+			Expect(err).To(BeNil())
+			Expect(deleted).To(HaveLen(3))
+			Expect(deleted[1].VersionId).To(Equal(aws.String("v2")))
+			Expect(deleted[2].Key).To(Equal(aws.String("key-2")))
+		})
+
+		It("should not call DeleteObjects when the bucket is empty", func(ctx SpecContext) {
+			mockS3.DeleteObjectsFunc = func(ctx context.Context, input *s3.DeleteObjectsInput, opts ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+				Fail("DeleteObjects should not be called")
+				return nil, nil
+			}
+
+			client, _ := s3client.InitS3Client(params)
+			client.S3Service = mockS3
+
+			err := client.DeleteObjectVersions(ctx, "old-bucket")
+			Expect(err).To(BeNil())
+		})
+
+		It("should return an error when some objects could not be deleted", func(ctx SpecContext) {
+			mockS3.ListObjectVersionsFunc = func(ctx context.Context, input *s3.ListObjectVersionsInput, opts ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error) {
+				return &s3.ListObjectVersionsOutput{
+					Versions: []types.ObjectVersion{{Key: aws.String("locked-key"), VersionId: aws.String("v1")}},
+				}, nil
+			}
+			mockS3.DeleteObjectsFunc = func(ctx context.Context, input *s3.DeleteObjectsInput, opts ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+				return &s3.DeleteObjectsOutput{
+					Errors: []types.Error{{Key: aws.String("locked-key"), Code: aws.String("AccessDenied"), Message: aws.String("Access Denied")}},
+				}, nil
+			}
+
+			client, _ := s3client.InitS3Client(params)
+			client.S3Service = mockS3
+
+			err := client.DeleteObjectVersions(ctx, "old-bucket")
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("locked-key"))
+			Expect(err.Error()).To(ContainSubstring("AccessDenied"))
+		})
+
+		It("should return the listing error", func(ctx SpecContext) {
+			mockS3.ListObjectVersionsFunc = func(ctx context.Context, input *s3.ListObjectVersionsInput, opts ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			client, _ := s3client.InitS3Client(params)
+			client.S3Service = mockS3
+
+			err := client.DeleteObjectVersions(ctx, "old-bucket")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Describe("AbortMultipartUploads", func() {
+		var mockS3 *MockS3Client
+
+		BeforeEach(func() {
+			mockS3 = &MockS3Client{}
+		})
+
+		It("should abort every in-progress upload", func(ctx SpecContext) {
+			mockS3.ListMultipartUploadsFunc = func(ctx context.Context, input *s3.ListMultipartUploadsInput, opts ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error) {
+				return &s3.ListMultipartUploadsOutput{
+					Uploads: []types.MultipartUpload{
+						{Key: aws.String("key-1"), UploadId: aws.String("upload-1")},
+						{Key: aws.String("key-2"), UploadId: aws.String("upload-2")},
+					},
+				}, nil
+			}
+
+			var aborted []string
+			mockS3.AbortMultipartUploadFunc = func(ctx context.Context, input *s3.AbortMultipartUploadInput, opts ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+				aborted = append(aborted, aws.ToString(input.UploadId))
+				return &s3.AbortMultipartUploadOutput{}, nil
+			}
+
+			client, _ := s3client.InitS3Client(params)
+			client.S3Service = mockS3
+
+			err := client.AbortMultipartUploads(ctx, "old-bucket")
+			Expect(err).To(BeNil())
+			Expect(aborted).To(Equal([]string{"upload-1", "upload-2"}))
+		})
+
+		It("should ignore uploads that no longer exist", func(ctx SpecContext) {
+			mockS3.ListMultipartUploadsFunc = func(ctx context.Context, input *s3.ListMultipartUploadsInput, opts ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error) {
+				return &s3.ListMultipartUploadsOutput{
+					Uploads: []types.MultipartUpload{{Key: aws.String("key-1"), UploadId: aws.String("upload-1")}},
+				}, nil
+			}
+			mockS3.AbortMultipartUploadFunc = func(ctx context.Context, input *s3.AbortMultipartUploadInput, opts ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+				return nil, &types.NoSuchUpload{}
+			}
+
+			client, _ := s3client.InitS3Client(params)
+			client.S3Service = mockS3
+
+			err := client.AbortMultipartUploads(ctx, "old-bucket")
+			Expect(err).To(BeNil())
+		})
+
+		It("should return other abort errors", func(ctx SpecContext) {
+			mockS3.ListMultipartUploadsFunc = func(ctx context.Context, input *s3.ListMultipartUploadsInput, opts ...func(*s3.Options)) (*s3.ListMultipartUploadsOutput, error) {
+				return &s3.ListMultipartUploadsOutput{
+					Uploads: []types.MultipartUpload{{Key: aws.String("key-1"), UploadId: aws.String("upload-1")}},
+				}, nil
+			}
+			mockS3.AbortMultipartUploadFunc = func(ctx context.Context, input *s3.AbortMultipartUploadInput, opts ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+				return nil, fmt.Errorf("SomeOtherError: Something went wrong")
+			}
+
+			client, _ := s3client.InitS3Client(params)
+			client.S3Service = mockS3
+
+			err := client.AbortMultipartUploads(ctx, "old-bucket")
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
+
+// generateCertificate returns a self-signed PEM certificate and its PEM private key
+func generateCertificate() ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).To(BeNil())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cosi-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).To(BeNil())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).To(BeNil())
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
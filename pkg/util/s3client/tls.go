@@ -0,0 +1,75 @@
+package s3client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+
+	"k8s.io/klog/v2"
+)
+
+// NewTLSConfig returns the TLS settings of the connections to the object storage provider.
+// Certificates are verified against TLSCert when set and against the system roots otherwise,
+// unless TLSInsecure explicitly disables the verification.
+// TLSClientCert and TLSClientKey authenticate the driver with a client certificate.
+func NewTLSConfig(params S3Params) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if params.TLSInsecure {
+		if len(params.TLSCert) > 0 {
+			return nil, fmt.Errorf("a TLS CA certificate cannot be used with insecure TLS")
+		}
+		klog.Warning("TLS certificate verification of the object storage provider is disabled")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	if len(params.TLSCert) > 0 {
+		caCertPool := x509.NewCertPool()
+		if ok := caCertPool.AppendCertsFromPEM(params.TLSCert); !ok {
+			return nil, fmt.Errorf("failed to parse the TLS CA certificate: no PEM certificate found")
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	if len(params.TLSClientCert) > 0 || len(params.TLSClientKey) > 0 {
+		if len(params.TLSClientCert) == 0 || len(params.TLSClientKey) == 0 {
+			return nil, fmt.Errorf("a TLS client certificate requires both a certificate and a key")
+		}
+		clientCert, err := tls.X509KeyPair(params.TLSClientCert, params.TLSClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return tlsConfig, nil
+}
+
+// ConfigureTLSTransport returns an HTTP transport using the TLS settings of the parameters,
+// with the proxy and timeout settings of the default transport.
+func ConfigureTLSTransport(params S3Params) (*http.Transport, error) {
+	tlsConfig, err := NewTLSConfig(params)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// NewHTTPClient returns the HTTP client of the S3, IAM and STS requests to the object storage provider.
+func NewHTTPClient(params S3Params) (*http.Client, error) {
+	transport, err := ConfigureTLSTransport(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: transport,
+	}, nil
+}